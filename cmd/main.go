@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/shanto-323/axis/config"
@@ -47,6 +48,16 @@ func main() {
 	errChan := make(chan error, 1)
 	signal.Notify(stopChan, os.Interrupt)
 
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+
+	go func() {
+		for range reloadChan {
+			logger.Info().Msg("reloading configuration")
+			server.Reload()
+		}
+	}()
+
 	go func() {
 		if err := server.Run(); err != nil {
 			errChan <- err
@@ -0,0 +1,341 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// DefaultProviderName is used for the legacy single-provider settings.
+	DefaultProviderName = "openrouter"
+	// DefaultModelName answers requests naming no model when DefaultModel
+	// is unset.
+	DefaultModelName = "llama-70b"
+
+	ProviderTypeOpenAI = "openai"
+	// ProviderTypeFake is a scripted in-process provider for offline runs.
+	ProviderTypeFake = "fake"
+)
+
+type AiManager struct {
+	// Provider and ApiKey configure a single OpenAI-compatible backend named
+	// "openrouter". They are ignored once Providers is set.
+	Provider string `koanf:"provider"`
+	ApiKey   string `koanf:"api_key"`
+
+	Providers map[string]ProviderConfig `koanf:"providers" validate:"dive"`
+
+	// CatalogFile points to a JSON model catalog. The built-in catalog is
+	// used when it is empty. The file is re-read on SIGHUP.
+	CatalogFile string `koanf:"catalog_file"`
+
+	// DefaultModel is the catalog alias that answers requests naming neither
+	// a model nor a persona with a default model. Empty means llama-70b.
+	DefaultModel string `koanf:"default_model"`
+
+	Discovery  DiscoveryConfig  `koanf:"discovery"`
+	Resilience ResilienceConfig `koanf:"resilience"`
+	Tools      ToolsConfig      `koanf:"tools"`
+
+	StructuredOutput StructuredOutputConfig `koanf:"structured_output"`
+	Reasoning        ReasoningConfig        `koanf:"reasoning"`
+	Images           ImagesConfig           `koanf:"images"`
+	Cache            CacheConfig            `koanf:"cache"`
+	Compare          CompareConfig          `koanf:"compare"`
+	Ensemble         EnsembleConfig         `koanf:"ensemble"`
+	Context          ContextConfig          `koanf:"context"`
+	Summary          SummaryConfig          `koanf:"summary"`
+
+	// Models adds catalog entries, or overrides catalog ones, keyed by alias.
+	Models map[string]ModelConfig `koanf:"models" validate:"dive"`
+}
+
+// DiscoveryConfig controls the background check of catalog models against
+// the providers' model listings.
+type DiscoveryConfig struct {
+	Enabled  bool          `koanf:"enabled"`
+	Interval time.Duration `koanf:"interval"`
+}
+
+// ResilienceConfig controls retries and per-model circuit breakers around
+// provider calls. Zero values fall back to defaults.
+type ResilienceConfig struct {
+	MaxAttempts      int           `koanf:"max_attempts"`
+	BaseDelay        time.Duration `koanf:"base_delay"`
+	MaxDelay         time.Duration `koanf:"max_delay"`
+	BreakerThreshold int           `koanf:"breaker_threshold"`
+	BreakerCooldown  time.Duration `koanf:"breaker_cooldown"`
+}
+
+// ToolsConfig controls server-side tool calling.
+type ToolsConfig struct {
+	// MaxIterations caps the model turns of one chat request. Zero means 5.
+	MaxIterations int            `koanf:"max_iterations"`
+	HTTP          HTTPToolConfig `koanf:"http"`
+}
+
+// HTTPToolConfig configures the http_request tool, which is only offered
+// when AllowedURLs is set.
+type HTTPToolConfig struct {
+	// AllowedURLs are URL prefixes the tool may call.
+	AllowedURLs []string      `koanf:"allowed_urls"`
+	Timeout     time.Duration `koanf:"timeout"`
+}
+
+// StructuredOutputConfig controls JSON answers requested via response_format.
+type StructuredOutputConfig struct {
+	// MaxRepairs is how often an invalid answer is sent back to the model
+	// with the validation errors. Zero means 2, negative disables repairs.
+	MaxRepairs int `koanf:"max_repairs"`
+}
+
+// ReasoningConfig controls how reasoning is kept out of streamed answers.
+type ReasoningConfig struct {
+	// HoldBytes is how much output of a think_opened model is held back
+	// waiting for </think> before it is streamed as answer. Zero means 16 KiB.
+	HoldBytes int `koanf:"hold_bytes"`
+}
+
+// ImagesConfig limits the images attached to chat requests.
+type ImagesConfig struct {
+	// MaxBytes is the size limit of a single decoded image. Zero means 5 MiB.
+	MaxBytes int `koanf:"max_bytes"`
+	// MaxCount is the number of images one request may carry. Zero means 4.
+	MaxCount int `koanf:"max_count"`
+}
+
+const (
+	defaultMaxImageBytes = 5 << 20
+	defaultMaxImages     = 4
+)
+
+// Limits returns the size limit of an image and the number of images a
+// request may carry, with the defaults applied.
+func (i ImagesConfig) Limits() (maxBytes, maxCount int) {
+	maxBytes, maxCount = i.MaxBytes, i.MaxCount
+	if maxBytes == 0 {
+		maxBytes = defaultMaxImageBytes
+	}
+	if maxCount == 0 {
+		maxCount = defaultMaxImages
+	}
+	return maxBytes, maxCount
+}
+
+const (
+	CacheBackendMemory   = "memory"
+	CacheBackendPostgres = "postgres"
+)
+
+// CacheConfig controls the exact-match response cache of /chat. The cache
+// is off while Backend is empty.
+type CacheConfig struct {
+	Backend string `koanf:"backend"`
+	// TTL is how long an answer is reused. Zero means one hour.
+	TTL time.Duration `koanf:"ttl"`
+	// MaxEntries bounds the cache; the oldest entries are evicted first.
+	// Zero means 1000.
+	MaxEntries int `koanf:"max_entries"`
+}
+
+// CompareConfig controls /chat/compare.
+type CompareConfig struct {
+	// Timeout bounds each model's answer. Zero means 60s.
+	Timeout time.Duration `koanf:"timeout"`
+}
+
+// EnsembleConfig controls /chat/ensemble. Candidates and the judge are
+// each bounded by the compare timeout.
+type EnsembleConfig struct {
+	// Judge is the model that judges requests naming none.
+	Judge string `koanf:"judge"`
+}
+
+// ContextConfig controls how conversation history is fitted into a model's
+// context window.
+type ContextConfig struct {
+	// OutputReserve is the room left for the answer when a request sets no
+	// max_tokens. Zero means 4096, capped at the model's max output tokens.
+	OutputReserve int `koanf:"output_reserve"`
+}
+
+// SummaryConfig controls the rolling summaries of long conversations.
+// Summaries are off while Model is empty.
+type SummaryConfig struct {
+	// Model is the catalog alias of the model that writes summaries, best a
+	// cheap one. Summaries by another model are regenerated.
+	Model string `koanf:"model"`
+	// A summary is written once the turns not yet summarized, apart from
+	// the KeepTurns most recent, reach AfterTurns turns or AfterTokens
+	// estimated tokens. Zero means 10 turns, 8000 tokens and 4 turns.
+	AfterTurns  int `koanf:"after_turns"`
+	AfterTokens int `koanf:"after_tokens"`
+	KeepTurns   int `koanf:"keep_turns"`
+	// Timeout bounds one summary call. Zero means 60s.
+	Timeout time.Duration `koanf:"timeout"`
+}
+
+// ModelConfig describes one catalog entry, either from the catalog file or
+// from the environment.
+type ModelConfig struct {
+	DisplayName   string   `koanf:"display_name" json:"display_name"`
+	Provider      string   `koanf:"provider" json:"provider" validate:"required"`
+	Model         string   `koanf:"model" json:"model" validate:"required"`
+	ContextLength int      `koanf:"context_length" json:"context_length"`
+	Modalities    []string `koanf:"modalities" json:"modalities"`
+	Reasoning     bool     `koanf:"reasoning" json:"reasoning"`
+	Enabled       *bool    `koanf:"enabled" json:"enabled"`
+
+	// ThinkOpened marks reasoning models whose chat template opens the
+	// <think> block in the prompt, so their output only closes it.
+	ThinkOpened bool `koanf:"think_opened" json:"think_opened"`
+
+	// StructuredOutputs marks models whose provider accepts response_format.
+	// Other models are asked for JSON in a system message.
+	StructuredOutputs bool `koanf:"structured_outputs" json:"structured_outputs"`
+
+	// MaxOutputTokens caps max_tokens on requests. Zero means context_length.
+	MaxOutputTokens int `koanf:"max_output_tokens" json:"max_output_tokens"`
+
+	// InputPrice and OutputPrice are USD per million prompt and completion tokens.
+	InputPrice  float64 `koanf:"input_price" json:"input_price"`
+	OutputPrice float64 `koanf:"output_price" json:"output_price"`
+
+	// Fallbacks are aliases tried in order when this model fails with a
+	// retryable upstream error.
+	Fallbacks []string `koanf:"fallbacks" json:"fallbacks"`
+}
+
+func (m ModelConfig) IsEnabled() bool {
+	return m.Enabled == nil || *m.Enabled
+}
+
+type ProviderConfig struct {
+	Type    string            `koanf:"type"`
+	BaseURL string            `koanf:"base_url"`
+	ApiKey  string            `koanf:"api_key"`
+	Timeout time.Duration     `koanf:"timeout"`
+	Headers map[string]string `koanf:"headers"`
+
+	// Cassette records the provider's HTTP traffic to a file, or replays
+	// it, depending on CassetteMode ("record" or "replay").
+	Cassette     string `koanf:"cassette"`
+	CassetteMode string `koanf:"cassette_mode"`
+
+	// Script and Latency configure fake providers: Script is a JSON file of
+	// canned responses, Latency the delay of every call.
+	Script  string        `koanf:"script"`
+	Latency time.Duration `koanf:"latency"`
+}
+
+// ProviderConfigs returns every configured provider keyed by name.
+func (a *AiManager) ProviderConfigs() map[string]ProviderConfig {
+	if len(a.Providers) == 0 {
+		return map[string]ProviderConfig{
+			DefaultProviderName: {
+				Type:    ProviderTypeOpenAI,
+				BaseURL: a.Provider,
+				ApiKey:  a.ApiKey,
+			},
+		}
+	}
+
+	providers := make(map[string]ProviderConfig, len(a.Providers))
+	for name, p := range a.Providers {
+		if p.Type == "" {
+			p.Type = ProviderTypeOpenAI
+		}
+		providers[name] = p
+	}
+	return providers
+}
+
+// ChatModel returns the alias that answers requests naming no model.
+func (a *AiManager) ChatModel() string {
+	if a.DefaultModel == "" {
+		return DefaultModelName
+	}
+	return a.DefaultModel
+}
+
+func (a *AiManager) Validate() error {
+	if len(a.Providers) == 0 && (a.Provider == "" || a.ApiKey == "") {
+		return fmt.Errorf("either ai_manager.provider and ai_manager.api_key or ai_manager.providers must be set")
+	}
+
+	if a.Discovery.Interval < 0 {
+		return fmt.Errorf("discovery interval must be non-negative")
+	}
+
+	r := a.Resilience
+	if r.MaxAttempts < 0 || r.BreakerThreshold < 0 || r.BaseDelay < 0 || r.MaxDelay < 0 || r.BreakerCooldown < 0 {
+		return fmt.Errorf("resilience settings must be non-negative")
+	}
+
+	if a.Tools.MaxIterations < 0 || a.Tools.HTTP.Timeout < 0 {
+		return fmt.Errorf("tool settings must be non-negative")
+	}
+
+	if a.Reasoning.HoldBytes < 0 {
+		return fmt.Errorf("reasoning hold bytes must be non-negative")
+	}
+
+	if a.Images.MaxBytes < 0 || a.Images.MaxCount < 0 {
+		return fmt.Errorf("image settings must be non-negative")
+	}
+
+	switch a.Cache.Backend {
+	case "", CacheBackendMemory, CacheBackendPostgres:
+	default:
+		return fmt.Errorf("cache: unsupported backend %s", a.Cache.Backend)
+	}
+	if a.Cache.TTL < 0 || a.Cache.MaxEntries < 0 {
+		return fmt.Errorf("cache settings must be non-negative")
+	}
+
+	if a.Compare.Timeout < 0 {
+		return fmt.Errorf("compare timeout must be non-negative")
+	}
+
+	if a.Context.OutputReserve < 0 {
+		return fmt.Errorf("context output reserve must be non-negative")
+	}
+
+	if a.Summary.AfterTurns < 0 || a.Summary.AfterTokens < 0 || a.Summary.KeepTurns < 0 || a.Summary.Timeout < 0 {
+		return fmt.Errorf("summary settings must be non-negative")
+	}
+
+	for _, allowed := range a.Tools.HTTP.AllowedURLs {
+		u, err := url.Parse(allowed)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("tools http: invalid allowed url %s", allowed)
+		}
+	}
+
+	for name, p := range a.Providers {
+		switch p.Type {
+		case "", ProviderTypeOpenAI:
+			if p.BaseURL == "" {
+				return fmt.Errorf("provider %s: base_url is required", name)
+			}
+		case ProviderTypeFake:
+		default:
+			return fmt.Errorf("provider %s: unsupported type %s", name, p.Type)
+		}
+		switch p.CassetteMode {
+		case "":
+		case "record", "replay":
+			if p.Cassette == "" {
+				return fmt.Errorf("provider %s: cassette is required for cassette mode %s", name, p.CassetteMode)
+			}
+		default:
+			return fmt.Errorf("provider %s: unsupported cassette mode %s", name, p.CassetteMode)
+		}
+		if p.Timeout < 0 || p.Latency < 0 {
+			return fmt.Errorf("provider %s: timeout and latency must be non-negative", name)
+		}
+	}
+
+	return nil
+}
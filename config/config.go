@@ -31,6 +31,8 @@ type Server struct {
 	IdleTimeout        int      `koanf:"idle_timeout" validate:"required"`
 	CORSAllowedOrigins []string `koanf:"cors_allowed_origins" validate:"required"`
 	JwtKey             string   `koanf:"jwt_key" validate:"required"`
+	// AdminUserIDs may read reports across all users.
+	AdminUserIDs []string `koanf:"admin_user_ids"`
 }
 
 type Database struct {
@@ -47,11 +49,6 @@ type Database struct {
 	ConnMaxIdleTime int    `koanf:"conn_max_idle_time" validate:"required"`
 }
 
-type AiManager struct {
-	Provider string `koanf:"provider" validate:"required"`
-	ApiKey   string `koanf:"api_key" validate:"required"`
-}
-
 func LoadConfig() (*Config, error) {
 	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stdout}).With().Timestamp().Logger()
 
@@ -72,6 +69,10 @@ func LoadConfig() (*Config, error) {
 		logger.Fatal().Err(err).Msg("could not unmarshal main ")
 	}
 
+	if err := config.AiManage.Validate(); err != nil {
+		logger.Fatal().Err(err).Msg("invalid ai manager config")
+	}
+
 	if config.Observability == nil {
 		config.Observability = DefaultObservabilityConfig()
 	}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
@@ -26,8 +27,32 @@ type Database interface {
 	CreateUser(ctx context.Context, user *dto.RegisterRequest) (*entity.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
 
+	CreateConversation(ctx context.Context, c *entity.Conversation) (*entity.Conversation, error)
+	GetConversationByID(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*entity.Conversation, error)
+
 	CreateConversationLog(ctx context.Context, cl *entity.ConversationLog) (*entity.ConversationLog, error)
 	GetConversationLogHistory(ctx context.Context, userId uuid.UUID, queryDto *dto.ConversationHistoryQuery) (*model.PaginatedResponse[entity.ConversationLog], error)
+	GetHistoryForLLM(ctx context.Context, conversationId uuid.UUID) (*[]entity.ConversationLog, error)
+	SearchConversationLogs(ctx context.Context, userId uuid.UUID, query string, limit int) ([]entity.ConversationLog, error)
+
+	CreateConversationSummary(ctx context.Context, s *entity.ConversationSummary) (*entity.ConversationSummary, error)
+	GetLatestConversationSummary(ctx context.Context, conversationId uuid.UUID) (*entity.ConversationSummary, error)
+
+	CreateImage(ctx context.Context, img *entity.Image) (*entity.Image, error)
+	GetImageByID(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*entity.Image, error)
+
+	GetCachedResponse(ctx context.Context, key string) (*dto.ConversationLogResponse, error)
+	SaveCachedResponse(ctx context.Context, key string, response *dto.ConversationLogResponse, expiresAt time.Time, maxEntries int) error
+
+	CreatePersona(ctx context.Context, p *entity.Persona) (*entity.Persona, error)
+	GetPersonaByID(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*entity.Persona, error)
+	ListPersonas(ctx context.Context, userId uuid.UUID) ([]entity.Persona, error)
+	UpdatePersona(ctx context.Context, p *entity.Persona) (*entity.Persona, error)
+	DeletePersona(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+
+	GetQuotaPlanByUserID(ctx context.Context, userId uuid.UUID) (*entity.QuotaPlan, error)
+	GetUsageTotals(ctx context.Context, userId uuid.UUID, dayStart, monthStart time.Time) (*entity.UsageTotals, error)
+	GetCostReport(ctx context.Context, query *dto.CostReportQuery) ([]entity.CostReportRow, error)
 }
 
 func New(cfg *config.Config, logger *zerolog.Logger, tracer trace.Tracer) (Database, error) {
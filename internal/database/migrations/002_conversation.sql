@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS conversations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    llm_model_name TEXT NOT NULL
+);
+
+CREATE INDEX idx_conversations_user_id ON conversations(user_id);
+
+ALTER TABLE conversation_logs
+    ADD COLUMN conversation_id UUID REFERENCES conversations(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_conversation_logs_conversation_id ON conversation_logs(conversation_id, timestamp);
@@ -0,0 +1,2 @@
+ALTER TABLE conversation_logs
+    ADD COLUMN fallback_from TEXT;
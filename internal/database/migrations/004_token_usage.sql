@@ -0,0 +1,6 @@
+ALTER TABLE conversation_logs
+    ADD COLUMN prompt_tokens INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN completion_tokens INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN total_tokens INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN finish_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN provider_response_id TEXT NOT NULL DEFAULT '';
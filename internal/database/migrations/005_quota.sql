@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS quota_plans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    name TEXT NOT NULL UNIQUE,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    -- NULL means unlimited
+    requests_per_day INTEGER,
+    tokens_per_day INTEGER,
+    requests_per_month INTEGER,
+    tokens_per_month INTEGER
+);
+
+CREATE UNIQUE INDEX idx_quota_plans_default ON quota_plans(is_default) WHERE is_default;
+
+INSERT INTO quota_plans (name, is_default, requests_per_day, tokens_per_day, requests_per_month, tokens_per_month)
+VALUES
+    ('free', TRUE, 200, 200000, 3000, 3000000),
+    ('unlimited', FALSE, NULL, NULL, NULL, NULL)
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE users
+    ADD COLUMN quota_plan_id UUID REFERENCES quota_plans(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_conversation_logs_user_id_timestamp ON conversation_logs(user_id, timestamp);
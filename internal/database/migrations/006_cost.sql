@@ -0,0 +1,5 @@
+-- Estimated cost in USD, computed from token usage and catalog prices.
+ALTER TABLE conversation_logs
+    ADD COLUMN cost DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_conversation_logs_timestamp ON conversation_logs(timestamp);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS personas (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    system_prompt TEXT NOT NULL,
+    -- empty means the chat request or server default decides
+    default_model TEXT NOT NULL DEFAULT '',
+    params JSONB NOT NULL DEFAULT '{}',
+    shared BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX idx_personas_user_id ON personas(user_id);
+CREATE INDEX idx_personas_shared ON personas(shared) WHERE shared;
+
+ALTER TABLE conversation_logs
+    ADD COLUMN persona_id UUID REFERENCES personas(id) ON DELETE SET NULL;
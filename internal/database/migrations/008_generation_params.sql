@@ -0,0 +1,3 @@
+-- Effective sampling parameters sent to the provider, to reproduce an answer.
+ALTER TABLE conversation_logs
+    ADD COLUMN params JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,3 @@
+-- Tool calls made while generating the answer, with their results.
+ALTER TABLE conversation_logs
+    ADD COLUMN tool_calls JSONB;
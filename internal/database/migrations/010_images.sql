@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS images (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    content_type TEXT NOT NULL,
+    size INTEGER NOT NULL,
+    sha256 TEXT NOT NULL,
+    data BYTEA NOT NULL
+);
+
+CREATE INDEX idx_images_user_id ON images(user_id);
+
+-- Images sent with the query, see images.
+ALTER TABLE conversation_logs
+    ADD COLUMN image_ids UUID[];
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS response_cache (
+    key TEXT PRIMARY KEY,
+    response JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_response_cache_created_at ON response_cache(created_at);
+
+-- Answers served from the response cache instead of the provider.
+ALTER TABLE conversation_logs
+    ADD COLUMN cached BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+-- Answers shared from an identical request's upstream call.
+ALTER TABLE conversation_logs
+    ADD COLUMN coalesced BOOLEAN NOT NULL DEFAULT FALSE;
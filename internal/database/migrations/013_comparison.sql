@@ -0,0 +1,5 @@
+-- Answers of one /chat/compare request share a comparison id.
+ALTER TABLE conversation_logs
+    ADD COLUMN comparison_id UUID;
+
+CREATE INDEX idx_conversation_logs_comparison_id ON conversation_logs(comparison_id) WHERE comparison_id IS NOT NULL;
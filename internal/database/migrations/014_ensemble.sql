@@ -0,0 +1,7 @@
+-- Candidate answers and the judge's verdict of one /chat/ensemble request
+-- share an ensemble id.
+ALTER TABLE conversation_logs
+    ADD COLUMN ensemble_id UUID,
+    ADD COLUMN ensemble_role TEXT CHECK (ensemble_role IN ('candidate', 'judge'));
+
+CREATE INDEX idx_conversation_logs_ensemble_id ON conversation_logs(ensemble_id) WHERE ensemble_id IS NOT NULL;
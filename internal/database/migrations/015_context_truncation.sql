@@ -0,0 +1,3 @@
+-- Conversation turns left out of the prompt to fit the context window.
+ALTER TABLE conversation_logs
+    ADD COLUMN truncation JSONB;
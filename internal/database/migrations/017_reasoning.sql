@@ -0,0 +1,4 @@
+-- Reasoning the model produced before its answer, kept apart from it.
+ALTER TABLE conversation_logs
+    ADD COLUMN reasoning TEXT,
+    ADD COLUMN reasoning_tokens INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+-- Generations cancelled by the client are stored with what was generated
+-- before the cancellation.
+ALTER TABLE conversation_logs
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'completed'
+        CHECK (status IN ('completed', 'cancelled'));
@@ -0,0 +1,6 @@
+-- Generations that spent tokens on an answer that was rejected, e.g. JSON
+-- that never matched the response_format, are stored as failed.
+ALTER TABLE conversation_logs
+    DROP CONSTRAINT conversation_logs_status_check,
+    ADD CONSTRAINT conversation_logs_status_check
+        CHECK (status IN ('completed', 'cancelled', 'failed'));
@@ -0,0 +1,42 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/model/entity"
+)
+
+func (db *DB) CreateConversation(ctx context.Context, c *entity.Conversation) (*entity.Conversation, error) {
+	c.ID = uuid.New()
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = time.Now()
+
+	db.mu.Lock()
+	db.pool[c.ID.String()] = c
+	db.mu.Unlock()
+
+	db.logger.Info().
+		Str("event", "new_conversation").
+		Str("user_id", c.UserID.String()).
+		Str("llm_model", c.LLMModelName).
+		Msg("new conversation created")
+
+	return c, nil
+}
+
+func (db *DB) GetConversationByID(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*entity.Conversation, error) {
+	db.mu.RLock()
+	v, ok := db.pool[id.String()]
+	db.mu.RUnlock()
+
+	c, isConversation := v.(*entity.Conversation)
+	if !ok || !isConversation || c.UserID != userId {
+		code := "CONVERSATION_NOT_FOUND"
+		return nil, errs.NewNotFoundError("conversation not found", true, &code)
+	}
+
+	return c, nil
+}
@@ -2,6 +2,9 @@ package mock
 
 import (
 	"context"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shanto-323/axis/internal/model"
@@ -11,10 +14,14 @@ import (
 
 func (db *DB) CreateConversationLog(ctx context.Context, cl *entity.ConversationLog) (*entity.ConversationLog, error) {
 	cl.ID = uuid.New()
+	cl.Timestamp = time.Now()
 
 	idString := cl.ID.String()
 
-	db.pool[idString] = &cl
+	db.mu.Lock()
+	db.pool[idString] = cl
+	db.mu.Unlock()
+
 	db.logger.Info().
 		Str("event", "new_log").
 		Str("user_id", cl.UserID.String()).
@@ -27,3 +34,57 @@ func (db *DB) CreateConversationLog(ctx context.Context, cl *entity.Conversation
 func (db *DB) GetConversationLogHistory(ctx context.Context, userId uuid.UUID, query *dto.ConversationHistoryQuery) (*model.PaginatedResponse[entity.ConversationLog], error) {
 	return nil, nil
 }
+
+func (db *DB) GetHistoryForLLM(ctx context.Context, conversationId uuid.UUID) (*[]entity.ConversationLog, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	logs := []entity.ConversationLog{}
+	for _, v := range db.pool {
+		cl, ok := v.(*entity.ConversationLog)
+		if !ok || cl.ConversationID == nil || *cl.ConversationID != conversationId {
+			continue
+		}
+		if cl.Status == entity.ConversationLogStatusFailed {
+			continue
+		}
+		if cl.Status == entity.ConversationLogStatusCancelled && cl.ResponseText == "" {
+			continue
+		}
+		logs = append(logs, *cl)
+	}
+
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].Timestamp.Before(logs[j].Timestamp)
+	})
+
+	return &logs, nil
+}
+
+func (db *DB) SearchConversationLogs(ctx context.Context, userId uuid.UUID, query string, limit int) ([]entity.ConversationLog, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	query = strings.ToLower(query)
+
+	logs := []entity.ConversationLog{}
+	for _, v := range db.pool {
+		cl, ok := v.(*entity.ConversationLog)
+		if !ok || cl.UserID != userId {
+			continue
+		}
+		if strings.Contains(strings.ToLower(cl.TextQuery), query) || strings.Contains(strings.ToLower(cl.ResponseText), query) {
+			logs = append(logs, *cl)
+		}
+	}
+
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].Timestamp.After(logs[j].Timestamp)
+	})
+
+	if len(logs) > limit {
+		logs = logs[:limit]
+	}
+
+	return logs, nil
+}
@@ -0,0 +1,38 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/internal/model/entity"
+)
+
+func (db *DB) CreateConversationSummary(ctx context.Context, s *entity.ConversationSummary) (*entity.ConversationSummary, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	key := "conversation_summaries:" + s.ConversationID.String()
+	versions, _ := db.pool[key].([]entity.ConversationSummary)
+
+	s.ID = uuid.New()
+	s.CreatedAt = time.Now()
+	s.Version = len(versions) + 1
+
+	db.pool[key] = append(versions, *s)
+
+	return s, nil
+}
+
+func (db *DB) GetLatestConversationSummary(ctx context.Context, conversationId uuid.UUID) (*entity.ConversationSummary, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	versions, _ := db.pool["conversation_summaries:"+conversationId.String()].([]entity.ConversationSummary)
+	if len(versions) == 0 {
+		return nil, nil
+	}
+
+	latest := versions[len(versions)-1]
+	return &latest, nil
+}
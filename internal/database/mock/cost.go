@@ -0,0 +1,62 @@
+package mock
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"github.com/shanto-323/axis/internal/model/entity"
+)
+
+func (db *DB) GetCostReport(ctx context.Context, query *dto.CostReportQuery) ([]entity.CostReportRow, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	type key struct {
+		userId uuid.UUID
+		model  string
+	}
+
+	groups := map[key]*entity.CostReportRow{}
+	for _, v := range db.pool {
+		cl, ok := v.(*entity.ConversationLog)
+		if !ok || cl.Timestamp.Before(query.FromTime) || !cl.Timestamp.Before(query.ToTime) {
+			continue
+		}
+		if query.UserID != nil && cl.UserID != *query.UserID {
+			continue
+		}
+		if query.Model != "" && cl.LLMModelName != query.Model {
+			continue
+		}
+
+		k := key{cl.UserID, cl.LLMModelName}
+		row, ok := groups[k]
+		if !ok {
+			row = &entity.CostReportRow{UserID: cl.UserID, LLMModelName: cl.LLMModelName}
+			groups[k] = row
+		}
+		row.Requests++
+		row.PromptTokens += cl.PromptTokens
+		row.CompletionTokens += cl.CompletionTokens
+		row.ReasoningTokens += cl.ReasoningTokens
+		row.Cost += cl.Cost
+	}
+
+	report := make([]entity.CostReportRow, 0, len(groups))
+	for _, row := range groups {
+		report = append(report, *row)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Cost != report[j].Cost {
+			return report[i].Cost > report[j].Cost
+		}
+		if report[i].UserID != report[j].UserID {
+			return report[i].UserID.String() < report[j].UserID.String()
+		}
+		return report[i].LLMModelName < report[j].LLMModelName
+	})
+
+	return report, nil
+}
@@ -0,0 +1,37 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/model/entity"
+)
+
+func (db *DB) CreateImage(ctx context.Context, img *entity.Image) (*entity.Image, error) {
+	img.ID = uuid.New()
+	img.CreatedAt = time.Now()
+
+	stored := *img
+
+	db.mu.Lock()
+	db.pool[img.ID.String()] = &stored
+	db.mu.Unlock()
+
+	return img, nil
+}
+
+func (db *DB) GetImageByID(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*entity.Image, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	img, ok := db.pool[id.String()].(*entity.Image)
+	if !ok || img.UserID != userId {
+		code := "IMAGE_NOT_FOUND"
+		return nil, errs.NewNotFoundError("image not found", true, &code)
+	}
+
+	found := *img
+	return &found, nil
+}
@@ -2,16 +2,16 @@ package mock
 
 import (
 	"context"
+	"sync"
 
-	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"github.com/shanto-323/axis/config"
-	"github.com/shanto-323/axis/internal/model/entity"
 )
 
 type MockDb map[string]any
 
 type DB struct {
+	mu     sync.RWMutex
 	pool   MockDb
 	logger *zerolog.Logger
 }
@@ -28,6 +28,3 @@ func New(config *config.Config, logger *zerolog.Logger) (*DB, error) {
 func (db *DB) Ping(ctx context.Context) error         { return nil }
 func (db *DB) IsInitialized(ctx context.Context) bool { return true }
 func (db *DB) Close() error                           { return nil }
-func (db *DB) GetHistoryForLLM(ctx context.Context, userId uuid.UUID) (*[]entity.ConversationLog, error) {
-	return nil, nil
-}
@@ -0,0 +1,98 @@
+package mock
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/model/entity"
+)
+
+func personaNotFound() error {
+	code := "PERSONA_NOT_FOUND"
+	return errs.NewNotFoundError("persona not found", true, &code)
+}
+
+func (db *DB) CreatePersona(ctx context.Context, p *entity.Persona) (*entity.Persona, error) {
+	p.ID = uuid.New()
+	p.CreatedAt = time.Now()
+	p.UpdatedAt = time.Now()
+
+	stored := *p
+
+	db.mu.Lock()
+	db.pool[p.ID.String()] = &stored
+	db.mu.Unlock()
+
+	return p, nil
+}
+
+func (db *DB) GetPersonaByID(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*entity.Persona, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	p, ok := db.pool[id.String()].(*entity.Persona)
+	if !ok || (p.UserID != userId && !p.Shared) {
+		return nil, personaNotFound()
+	}
+
+	persona := *p
+	return &persona, nil
+}
+
+func (db *DB) ListPersonas(ctx context.Context, userId uuid.UUID) ([]entity.Persona, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	personas := []entity.Persona{}
+	for _, v := range db.pool {
+		p, ok := v.(*entity.Persona)
+		if ok && (p.UserID == userId || p.Shared) {
+			personas = append(personas, *p)
+		}
+	}
+
+	sort.Slice(personas, func(i, j int) bool {
+		iOwn, jOwn := personas[i].UserID == userId, personas[j].UserID == userId
+		if iOwn != jOwn {
+			return iOwn
+		}
+		return personas[i].Name < personas[j].Name
+	})
+
+	return personas, nil
+}
+
+func (db *DB) UpdatePersona(ctx context.Context, p *entity.Persona) (*entity.Persona, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	existing, ok := db.pool[p.ID.String()].(*entity.Persona)
+	if !ok || existing.UserID != p.UserID {
+		return nil, personaNotFound()
+	}
+
+	p.CreatedAt = existing.CreatedAt
+	p.UpdatedAt = time.Now()
+
+	stored := *p
+	db.pool[p.ID.String()] = &stored
+
+	return p, nil
+}
+
+func (db *DB) DeletePersona(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	p, ok := db.pool[id.String()].(*entity.Persona)
+	if !ok || p.UserID != userId {
+		return personaNotFound()
+	}
+
+	delete(db.pool, id.String())
+
+	return nil
+}
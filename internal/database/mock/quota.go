@@ -0,0 +1,60 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/internal/model/entity"
+)
+
+// The mock database has no plans table, every user gets an unlimited plan.
+func (db *DB) GetQuotaPlanByUserID(ctx context.Context, userId uuid.UUID) (*entity.QuotaPlan, error) {
+	plan := &entity.QuotaPlan{
+		Name:      "unlimited",
+		IsDefault: true,
+	}
+	return plan, nil
+}
+
+func (db *DB) GetUsageTotals(ctx context.Context, userId uuid.UUID, dayStart, monthStart time.Time) (*entity.UsageTotals, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	totals := &entity.UsageTotals{}
+	add := func(at time.Time, requests, tokens, reasoningTokens int) {
+		totals.RequestsThisMonth += requests
+		totals.TokensThisMonth += tokens
+		totals.ReasoningTokensThisMonth += reasoningTokens
+		if !at.Before(dayStart) {
+			totals.RequestsToday += requests
+			totals.TokensToday += tokens
+			totals.ReasoningTokensToday += reasoningTokens
+		}
+	}
+
+	for _, v := range db.pool {
+		switch v := v.(type) {
+		case *entity.ConversationLog:
+			if v.UserID != userId || v.Timestamp.Before(monthStart) {
+				continue
+			}
+			// Cached and coalesced answers spent no tokens of their own.
+			if v.Cached || v.Coalesced {
+				add(v.Timestamp, 1, 0, 0)
+				continue
+			}
+			add(v.Timestamp, 1, v.TotalTokens, v.ReasoningTokens)
+		case []entity.ConversationSummary:
+			for _, s := range v {
+				c, ok := db.pool[s.ConversationID.String()].(*entity.Conversation)
+				if !ok || c.UserID != userId || s.CreatedAt.Before(monthStart) {
+					continue
+				}
+				add(s.CreatedAt, 0, s.PromptTokens+s.CompletionTokens, 0)
+			}
+		}
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,39 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/shanto-323/axis/internal/model/dto"
+)
+
+type cachedResponse struct {
+	response  dto.ConversationLogResponse
+	expiresAt time.Time
+}
+
+// The mock keeps no size limit on cached responses.
+func (db *DB) GetCachedResponse(ctx context.Context, key string) (*dto.ConversationLogResponse, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	entry, ok := db.pool["response_cache:"+key].(*cachedResponse)
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, nil
+	}
+
+	response := entry.response
+	return &response, nil
+}
+
+func (db *DB) SaveCachedResponse(ctx context.Context, key string, response *dto.ConversationLogResponse, expiresAt time.Time, maxEntries int) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.pool["response_cache:"+key] = &cachedResponse{
+		response:  *response,
+		expiresAt: expiresAt,
+	}
+
+	return nil
+}
@@ -20,7 +20,10 @@ func (db *DB) CreateUser(ctx context.Context, userDto *dto.RegisterRequest) (*en
 	userEntity.CreatedAt = time.Now()
 	userEntity.UpdatedAt = time.Now()
 
+	db.mu.Lock()
 	db.pool[userDto.Email] = &userEntity
+	db.mu.Unlock()
+
 	db.logger.Info().
 		Str("event", "user_created").
 		Str("email", userDto.Email).
@@ -30,7 +33,9 @@ func (db *DB) CreateUser(ctx context.Context, userDto *dto.RegisterRequest) (*en
 }
 
 func (db *DB) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+	db.mu.RLock()
 	user, ok := db.pool[email]
+	db.mu.RUnlock()
 	if !ok {
 		db.logger.Warn().Msg("no user found")
 		return nil, fmt.Errorf("no user found")
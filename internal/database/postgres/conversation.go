@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/model/entity"
+)
+
+func (db *DB) CreateConversation(ctx context.Context, c *entity.Conversation) (*entity.Conversation, error) {
+	query := `
+		INSERT INTO conversations (
+			user_id,
+			title,
+			llm_model_name
+		)
+		VALUES (
+			@user_id,
+			@title,
+			@llm_model_name
+		)
+		RETURNING
+			id,
+			created_at,
+			updated_at
+	`
+
+	err := db.pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"user_id":        c.UserID,
+		"title":          c.Title,
+		"llm_model_name": c.LLMModelName,
+	}).Scan(
+		&c.ID,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NewInternalServerError()
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (db *DB) GetConversationByID(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*entity.Conversation, error) {
+	query := `
+		SELECT
+			id,
+			created_at,
+			updated_at,
+			user_id,
+			title,
+			llm_model_name
+		FROM
+			conversations
+		WHERE
+			id = @id
+			AND user_id = @user_id
+	`
+
+	c := &entity.Conversation{}
+
+	err := db.pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"id":      id,
+		"user_id": userId,
+	}).Scan(
+		&c.ID,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+		&c.UserID,
+		&c.Title,
+		&c.LLMModelName,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			code := "CONVERSATION_NOT_FOUND"
+			return nil, errs.NewNotFoundError("conversation not found", true, &code)
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
@@ -17,31 +17,91 @@ func (db *DB) CreateConversationLog(ctx context.Context, cl *entity.Conversation
 	query := `
 		INSERT INTO conversation_logs (
 			user_id,
+			conversation_id,
 			text_query,
 			response_text,
-			llm_model_name
+			reasoning,
+			status,
+			llm_model_name,
+			fallback_from,
+			prompt_tokens,
+			completion_tokens,
+			total_tokens,
+			reasoning_tokens,
+			finish_reason,
+			provider_response_id,
+			cost,
+			persona_id,
+			params,
+			tool_calls,
+			image_ids,
+			cached,
+			coalesced,
+			comparison_id,
+			ensemble_id,
+			ensemble_role,
+			truncation
 		)
 		VALUES (
 			@user_id,
+			@conversation_id,
 			@text_query,
 			@response_text,
-			@llm_model_name
+			@reasoning,
+			@status,
+			@llm_model_name,
+			@fallback_from,
+			@prompt_tokens,
+			@completion_tokens,
+			@total_tokens,
+			@reasoning_tokens,
+			@finish_reason,
+			@provider_response_id,
+			@cost,
+			@persona_id,
+			@params,
+			@tool_calls,
+			@image_ids,
+			@cached,
+			@coalesced,
+			@comparison_id,
+			@ensemble_id,
+			@ensemble_role,
+			@truncation
 		)	
 		RETURNING 
-			* 
+			id,
+			timestamp
 	`
 
 	err := db.pool.QueryRow(ctx, query, pgx.NamedArgs{
-		"user_id":        cl.UserID,
-		"text_query":     cl.TextQuery,
-		"response_text":  cl.ResponseText,
-		"llm_model_name": cl.LLMModelName,
+		"user_id":              cl.UserID,
+		"conversation_id":      cl.ConversationID,
+		"text_query":           cl.TextQuery,
+		"response_text":        cl.ResponseText,
+		"reasoning":            cl.Reasoning,
+		"status":               cl.Status,
+		"llm_model_name":       cl.LLMModelName,
+		"fallback_from":        cl.FallbackFrom,
+		"prompt_tokens":        cl.PromptTokens,
+		"completion_tokens":    cl.CompletionTokens,
+		"total_tokens":         cl.TotalTokens,
+		"reasoning_tokens":     cl.ReasoningTokens,
+		"finish_reason":        cl.FinishReason,
+		"provider_response_id": cl.ProviderResponseID,
+		"cost":                 cl.Cost,
+		"persona_id":           cl.PersonaID,
+		"params":               cl.Params,
+		"tool_calls":           cl.ToolCalls,
+		"image_ids":            cl.ImageIDs,
+		"cached":               cl.Cached,
+		"coalesced":            cl.Coalesced,
+		"comparison_id":        cl.ComparisonID,
+		"ensemble_id":          cl.EnsembleID,
+		"ensemble_role":        cl.EnsembleRole,
+		"truncation":           cl.Truncation,
 	}).Scan(
 		&cl.ID,
-		&cl.UserID,
-		&cl.TextQuery,
-		&cl.ResponseText,
-		&cl.LLMModelName,
 		&cl.Timestamp,
 	)
 
@@ -129,3 +189,70 @@ func (db *DB) GetConversationLogHistory(
 		TotalPages: (total + *queryDto.Limit - 1) / *queryDto.Limit,
 	}, nil
 }
+
+// GetHistoryForLLM leaves out failed turns and turns cancelled before any
+// answer was generated.
+func (db *DB) GetHistoryForLLM(ctx context.Context, conversationId uuid.UUID) (*[]entity.ConversationLog, error) {
+	query := `
+		SELECT
+			*
+		FROM
+			conversation_logs
+		WHERE
+			conversation_id=@conversation_id
+			AND status <> 'failed'
+			AND NOT (status = 'cancelled' AND response_text = '')
+		ORDER BY
+			timestamp ASC
+	`
+
+	rows, err := db.pool.Query(ctx, query, pgx.NamedArgs{
+		"conversation_id": conversationId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute history query")
+	}
+
+	logs, err := pgx.CollectRows(rows, pgx.RowToStructByName[entity.ConversationLog])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows")
+	}
+
+	return &logs, nil
+}
+
+// SearchConversationLogs finds the user's logs whose query or answer contains
+// query, case insensitive, newest first.
+func (db *DB) SearchConversationLogs(ctx context.Context, userId uuid.UUID, query string, limit int) ([]entity.ConversationLog, error) {
+	sql := `
+		SELECT
+			*
+		FROM
+			conversation_logs
+		WHERE
+			user_id = @user_id
+			AND (
+				strpos(lower(text_query), lower(@query)) > 0
+				OR strpos(lower(response_text), lower(@query)) > 0
+			)
+		ORDER BY
+			timestamp DESC
+		LIMIT @limit
+	`
+
+	rows, err := db.pool.Query(ctx, sql, pgx.NamedArgs{
+		"user_id": userId,
+		"query":   query,
+		"limit":   limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute search query")
+	}
+
+	logs, err := pgx.CollectRows(rows, pgx.RowToStructByName[entity.ConversationLog])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows")
+	}
+
+	return logs, nil
+}
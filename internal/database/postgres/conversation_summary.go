@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/model/entity"
+)
+
+// CreateConversationSummary stores s as the next version of the
+// conversation's summary.
+func (db *DB) CreateConversationSummary(ctx context.Context, s *entity.ConversationSummary) (*entity.ConversationSummary, error) {
+	query := `
+		INSERT INTO conversation_summaries (
+			conversation_id,
+			version,
+			model,
+			prompt_version,
+			covered_turns,
+			summary,
+			prompt_tokens,
+			completion_tokens,
+			cost
+		)
+		VALUES (
+			@conversation_id,
+			(SELECT COALESCE(MAX(version), 0) + 1 FROM conversation_summaries WHERE conversation_id = @conversation_id),
+			@model,
+			@prompt_version,
+			@covered_turns,
+			@summary,
+			@prompt_tokens,
+			@completion_tokens,
+			@cost
+		)
+		RETURNING
+			id,
+			created_at,
+			version
+	`
+
+	err := db.pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"conversation_id":   s.ConversationID,
+		"model":             s.Model,
+		"prompt_version":    s.PromptVersion,
+		"covered_turns":     s.CoveredTurns,
+		"summary":           s.Summary,
+		"prompt_tokens":     s.PromptTokens,
+		"completion_tokens": s.CompletionTokens,
+		"cost":              s.Cost,
+	}).Scan(
+		&s.ID,
+		&s.CreatedAt,
+		&s.Version,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NewInternalServerError()
+		}
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// GetLatestConversationSummary returns nil when the conversation has no
+// summary yet.
+func (db *DB) GetLatestConversationSummary(ctx context.Context, conversationId uuid.UUID) (*entity.ConversationSummary, error) {
+	query := `
+		SELECT
+			*
+		FROM
+			conversation_summaries
+		WHERE
+			conversation_id = @conversation_id
+		ORDER BY
+			version DESC
+		LIMIT 1
+	`
+
+	rows, err := db.pool.Query(ctx, query, pgx.NamedArgs{
+		"conversation_id": conversationId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute summary query: %w", err)
+	}
+
+	summary, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[entity.ConversationSummary])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to collect summary: %w", err)
+	}
+
+	return &summary, nil
+}
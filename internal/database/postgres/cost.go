@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"github.com/shanto-323/axis/internal/model/entity"
+)
+
+func (db *DB) GetCostReport(ctx context.Context, queryDto *dto.CostReportQuery) ([]entity.CostReportRow, error) {
+	query := `
+		SELECT
+			user_id,
+			llm_model_name,
+			COUNT(*) AS requests,
+			COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens,
+			COALESCE(SUM(completion_tokens), 0) AS completion_tokens,
+			COALESCE(SUM(reasoning_tokens), 0) AS reasoning_tokens,
+			COALESCE(SUM(cost), 0) AS cost
+		FROM
+			conversation_logs
+		WHERE
+			timestamp >= @from
+			AND timestamp < @to
+			AND (@user_id::uuid IS NULL OR user_id = @user_id)
+			AND (@model = '' OR llm_model_name = @model)
+		GROUP BY
+			user_id,
+			llm_model_name
+		ORDER BY
+			cost DESC,
+			user_id,
+			llm_model_name
+	`
+
+	rows, err := db.pool.Query(ctx, query, pgx.NamedArgs{
+		"from":    queryDto.FromTime,
+		"to":      queryDto.ToTime,
+		"user_id": queryDto.UserID,
+		"model":   queryDto.Model,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute cost report query")
+	}
+
+	report, err := pgx.CollectRows(rows, pgx.RowToStructByName[entity.CostReportRow])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows")
+	}
+
+	return report, nil
+}
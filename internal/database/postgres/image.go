@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/model/entity"
+)
+
+func imageNotFound() error {
+	code := "IMAGE_NOT_FOUND"
+	return errs.NewNotFoundError("image not found", true, &code)
+}
+
+func (db *DB) CreateImage(ctx context.Context, img *entity.Image) (*entity.Image, error) {
+	query := `
+		INSERT INTO images (
+			user_id,
+			content_type,
+			size,
+			sha256,
+			data
+		)
+		VALUES (
+			@user_id,
+			@content_type,
+			@size,
+			@sha256,
+			@data
+		)
+		RETURNING
+			id,
+			created_at
+	`
+
+	err := db.pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"user_id":      img.UserID,
+		"content_type": img.ContentType,
+		"size":         img.Size,
+		"sha256":       img.SHA256,
+		"data":         img.Data,
+	}).Scan(
+		&img.ID,
+		&img.CreatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NewInternalServerError()
+		}
+		return nil, err
+	}
+
+	return img, nil
+}
+
+// GetImageByID returns an image owned by the user.
+func (db *DB) GetImageByID(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*entity.Image, error) {
+	query := `
+		SELECT
+			*
+		FROM
+			images
+		WHERE
+			id = @id
+			AND user_id = @user_id
+	`
+
+	rows, err := db.pool.Query(ctx, query, pgx.NamedArgs{
+		"id":      id,
+		"user_id": userId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute image query")
+	}
+
+	img, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entity.Image])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, imageNotFound()
+		}
+		return nil, err
+	}
+
+	return img, nil
+}
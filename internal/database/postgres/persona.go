@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/model/entity"
+)
+
+func personaNotFound() error {
+	code := "PERSONA_NOT_FOUND"
+	return errs.NewNotFoundError("persona not found", true, &code)
+}
+
+func (db *DB) CreatePersona(ctx context.Context, p *entity.Persona) (*entity.Persona, error) {
+	query := `
+		INSERT INTO personas (
+			user_id,
+			name,
+			description,
+			system_prompt,
+			default_model,
+			params,
+			shared
+		)
+		VALUES (
+			@user_id,
+			@name,
+			@description,
+			@system_prompt,
+			@default_model,
+			@params,
+			@shared
+		)
+		RETURNING
+			id,
+			created_at,
+			updated_at
+	`
+
+	err := db.pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"user_id":       p.UserID,
+		"name":          p.Name,
+		"description":   p.Description,
+		"system_prompt": p.SystemPrompt,
+		"default_model": p.DefaultModel,
+		"params":        p.Params,
+		"shared":        p.Shared,
+	}).Scan(
+		&p.ID,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.NewInternalServerError()
+		}
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// GetPersonaByID returns a persona the user owns or one that is shared.
+func (db *DB) GetPersonaByID(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*entity.Persona, error) {
+	query := `
+		SELECT
+			*
+		FROM
+			personas
+		WHERE
+			id = @id
+			AND (user_id = @user_id OR shared)
+	`
+
+	rows, err := db.pool.Query(ctx, query, pgx.NamedArgs{
+		"id":      id,
+		"user_id": userId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute persona query")
+	}
+
+	p, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entity.Persona])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, personaNotFound()
+		}
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// ListPersonas returns the user's own personas followed by shared ones.
+func (db *DB) ListPersonas(ctx context.Context, userId uuid.UUID) ([]entity.Persona, error) {
+	query := `
+		SELECT
+			*
+		FROM
+			personas
+		WHERE
+			user_id = @user_id
+			OR shared
+		ORDER BY
+			user_id <> @user_id,
+			name
+	`
+
+	rows, err := db.pool.Query(ctx, query, pgx.NamedArgs{
+		"user_id": userId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute persona query")
+	}
+
+	personas, err := pgx.CollectRows(rows, pgx.RowToStructByName[entity.Persona])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows")
+	}
+
+	return personas, nil
+}
+
+// UpdatePersona only changes personas owned by p.UserID.
+func (db *DB) UpdatePersona(ctx context.Context, p *entity.Persona) (*entity.Persona, error) {
+	query := `
+		UPDATE
+			personas
+		SET
+			name = @name,
+			description = @description,
+			system_prompt = @system_prompt,
+			default_model = @default_model,
+			params = @params,
+			shared = @shared,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = @id
+			AND user_id = @user_id
+		RETURNING
+			created_at,
+			updated_at
+	`
+
+	err := db.pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"id":            p.ID,
+		"user_id":       p.UserID,
+		"name":          p.Name,
+		"description":   p.Description,
+		"system_prompt": p.SystemPrompt,
+		"default_model": p.DefaultModel,
+		"params":        p.Params,
+		"shared":        p.Shared,
+	}).Scan(
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, personaNotFound()
+		}
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (db *DB) DeletePersona(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	query := `
+		DELETE FROM
+			personas
+		WHERE
+			id = @id
+			AND user_id = @user_id
+	`
+
+	tag, err := db.pool.Exec(ctx, query, pgx.NamedArgs{
+		"id":      id,
+		"user_id": userId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return personaNotFound()
+	}
+
+	return nil
+}
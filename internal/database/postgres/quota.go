@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/model/entity"
+)
+
+func (db *DB) GetQuotaPlanByUserID(ctx context.Context, userId uuid.UUID) (*entity.QuotaPlan, error) {
+	// Users without an assigned plan fall back to the default one.
+	query := `
+		SELECT
+			p.id,
+			p.created_at,
+			p.updated_at,
+			p.name,
+			p.is_default,
+			p.requests_per_day,
+			p.tokens_per_day,
+			p.requests_per_month,
+			p.tokens_per_month
+		FROM
+			quota_plans p
+		LEFT JOIN
+			users u ON u.quota_plan_id = p.id AND u.id = @user_id
+		WHERE
+			u.id IS NOT NULL
+			OR p.is_default
+		ORDER BY
+			u.id IS NULL
+		LIMIT 1
+	`
+
+	plan := &entity.QuotaPlan{}
+
+	err := db.pool.QueryRow(ctx, query, pgx.NamedArgs{"user_id": userId}).Scan(
+		&plan.ID,
+		&plan.CreatedAt,
+		&plan.UpdatedAt,
+		&plan.Name,
+		&plan.IsDefault,
+		&plan.RequestsPerDay,
+		&plan.TokensPerDay,
+		&plan.RequestsPerMonth,
+		&plan.TokensPerMonth,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			code := "QUOTA_PLAN_NOT_FOUND"
+			return nil, errs.NewNotFoundError("no quota plan found", false, &code)
+		}
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (db *DB) GetUsageTotals(ctx context.Context, userId uuid.UUID, dayStart, monthStart time.Time) (*entity.UsageTotals, error) {
+	// Cached and coalesced answers count as requests but spent no tokens of
+	// their own. Summaries spend tokens without being a request.
+	query := `
+		WITH usage AS (
+			SELECT
+				timestamp AS at,
+				1 AS requests,
+				CASE WHEN cached OR coalesced THEN 0 ELSE total_tokens END AS tokens,
+				CASE WHEN cached OR coalesced THEN 0 ELSE reasoning_tokens END AS reasoning_tokens
+			FROM
+				conversation_logs
+			WHERE
+				user_id = @user_id
+				AND timestamp >= @month_start
+			UNION ALL
+			SELECT
+				s.created_at,
+				0,
+				s.prompt_tokens + s.completion_tokens,
+				0
+			FROM
+				conversation_summaries s
+			JOIN
+				conversations c ON c.id = s.conversation_id
+			WHERE
+				c.user_id = @user_id
+				AND s.created_at >= @month_start
+		)
+		SELECT
+			COALESCE(SUM(requests) FILTER (WHERE at >= @day_start), 0),
+			COALESCE(SUM(tokens) FILTER (WHERE at >= @day_start), 0),
+			COALESCE(SUM(reasoning_tokens) FILTER (WHERE at >= @day_start), 0),
+			COALESCE(SUM(requests), 0),
+			COALESCE(SUM(tokens), 0),
+			COALESCE(SUM(reasoning_tokens), 0)
+		FROM
+			usage
+	`
+
+	totals := &entity.UsageTotals{}
+
+	err := db.pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"user_id":     userId,
+		"day_start":   dayStart,
+		"month_start": monthStart,
+	}).Scan(
+		&totals.RequestsToday,
+		&totals.TokensToday,
+		&totals.ReasoningTokensToday,
+		&totals.RequestsThisMonth,
+		&totals.TokensThisMonth,
+		&totals.ReasoningTokensThisMonth,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shanto-323/axis/internal/model/dto"
+)
+
+// GetCachedResponse returns nil when the key is unknown or expired.
+func (db *DB) GetCachedResponse(ctx context.Context, key string) (*dto.ConversationLogResponse, error) {
+	query := `
+		SELECT
+			response
+		FROM
+			response_cache
+		WHERE
+			key = @key
+			AND expires_at > NOW()
+	`
+
+	var response dto.ConversationLogResponse
+	err := db.pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"key": key,
+	}).Scan(&response)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to execute response cache query: %w", err)
+	}
+
+	return &response, nil
+}
+
+// SaveCachedResponse stores an entry, then drops expired entries and the
+// oldest ones beyond maxEntries.
+func (db *DB) SaveCachedResponse(ctx context.Context, key string, response *dto.ConversationLogResponse, expiresAt time.Time, maxEntries int) error {
+	query := `
+		INSERT INTO response_cache (
+			key,
+			response,
+			expires_at
+		)
+		VALUES (
+			@key,
+			@response,
+			@expires_at
+		)
+		ON CONFLICT (key) DO UPDATE SET
+			response = EXCLUDED.response,
+			created_at = CURRENT_TIMESTAMP,
+			expires_at = EXCLUDED.expires_at
+	`
+
+	_, err := db.pool.Exec(ctx, query, pgx.NamedArgs{
+		"key":        key,
+		"response":   response,
+		"expires_at": expiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save cached response: %w", err)
+	}
+
+	evict := `
+		DELETE FROM
+			response_cache
+		WHERE
+			expires_at <= NOW()
+			OR key IN (
+				SELECT
+					key
+				FROM
+					response_cache
+				ORDER BY
+					created_at DESC
+				OFFSET @max_entries
+			)
+	`
+
+	_, err = db.pool.Exec(ctx, evict, pgx.NamedArgs{
+		"max_entries": maxEntries,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to evict cached responses: %w", err)
+	}
+
+	return nil
+}
@@ -61,6 +61,67 @@ func NewInternalServerError() *HTTPError {
 	}
 }
 
+func NewTooManyRequestsError(message string, override bool, code *string) *HTTPError {
+	formattedCode := MakeUpperCaseWithUnderscores(http.StatusText(http.StatusTooManyRequests))
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	return &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusTooManyRequests,
+		Override: override,
+	}
+}
+
+func NewServiceUnavailableError(message string, override bool, code *string) *HTTPError {
+	formattedCode := MakeUpperCaseWithUnderscores(http.StatusText(http.StatusServiceUnavailable))
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	return &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusServiceUnavailable,
+		Override: override,
+	}
+}
+
+func NewRequestEntityTooLargeError(message string, override bool, code *string) *HTTPError {
+	formattedCode := MakeUpperCaseWithUnderscores(http.StatusText(http.StatusRequestEntityTooLarge))
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	return &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusRequestEntityTooLarge,
+		Override: override,
+	}
+}
+
+func NewUnprocessableEntityError(message string, override bool, code *string, errors []FieldError) *HTTPError {
+	formattedCode := MakeUpperCaseWithUnderscores(http.StatusText(http.StatusUnprocessableEntity))
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	return &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusUnprocessableEntity,
+		Override: override,
+		Errors:   errors,
+	}
+}
+
 func NewTimeoutError() *HTTPError {
 	return &HTTPError{
 		Code:     MakeUpperCaseWithUnderscores(http.StatusText(http.StatusGatewayTimeout)),
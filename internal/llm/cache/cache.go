@@ -0,0 +1,97 @@
+// Package cache keeps generated answers so identical chat requests can be
+// answered without calling the provider again.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/shanto-323/axis/config"
+	"github.com/shanto-323/axis/internal/model"
+	"github.com/shanto-323/axis/internal/model/dto"
+)
+
+const (
+	defaultTTL        = time.Hour
+	defaultMaxEntries = 1000
+)
+
+type Cache interface {
+	// Get returns nil when the key is not cached or has expired.
+	Get(ctx context.Context, key string) (*dto.ConversationLogResponse, error)
+	Set(ctx context.Context, key string, response *dto.ConversationLogResponse) error
+}
+
+// Store persists cache entries for the Postgres backend.
+type Store interface {
+	GetCachedResponse(ctx context.Context, key string) (*dto.ConversationLogResponse, error)
+	SaveCachedResponse(ctx context.Context, key string, response *dto.ConversationLogResponse, expiresAt time.Time, maxEntries int) error
+}
+
+// New returns the configured cache, or nil when caching is off.
+func New(cfg config.CacheConfig, store Store) Cache {
+	ttl := cfg.TTL
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	maxEntries := cfg.MaxEntries
+	if maxEntries == 0 {
+		maxEntries = defaultMaxEntries
+	}
+
+	switch cfg.Backend {
+	case config.CacheBackendMemory:
+		return NewMemory(ttl, maxEntries)
+	case config.CacheBackendPostgres:
+		return NewPostgres(store, ttl, maxEntries)
+	default:
+		return nil
+	}
+}
+
+type keyMessage struct {
+	Role    string   `json:"role"`
+	Content string   `json:"content"`
+	Images  []string `json:"images,omitempty"`
+}
+
+type keyInput struct {
+	Model          string                 `json:"model"`
+	Messages       []keyMessage           `json:"messages"`
+	Params         model.GenerationParams `json:"params"`
+	ResponseFormat *dto.ResponseFormat    `json:"response_format"`
+}
+
+// Key hashes everything that decides an answer: the model alias, the
+// messages with normalized whitespace and line endings, the generation
+// parameters and the response format. Images are included by their hash.
+func Key(modelName string, messages []dto.ChatMessage, params model.GenerationParams, format *dto.ResponseFormat) string {
+	input := keyInput{
+		Model:          modelName,
+		Messages:       make([]keyMessage, 0, len(messages)),
+		Params:         params,
+		ResponseFormat: format,
+	}
+
+	for _, m := range messages {
+		km := keyMessage{Role: m.Role, Content: normalize(m.Content)}
+		for _, img := range m.Images {
+			sum := sha256.Sum256(img.Data)
+			km.Images = append(km.Images, hex.EncodeToString(sum[:]))
+		}
+		input.Messages = append(input.Messages, km)
+	}
+
+	// Only plain data is marshaled, this cannot fail.
+	b, _ := json.Marshal(input)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func normalize(content string) string {
+	return strings.TrimSpace(strings.ReplaceAll(content, "\r\n", "\n"))
+}
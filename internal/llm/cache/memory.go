@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/shanto-323/axis/internal/model/dto"
+)
+
+type memoryEntry struct {
+	key       string
+	response  dto.ConversationLogResponse
+	expiresAt time.Time
+}
+
+// Memory is an in-process LRU cache. Entries are lost on restart and not
+// shared between instances.
+type Memory struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func NewMemory(ttl time.Duration, maxEntries int) *Memory {
+	return &Memory{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    map[string]*list.Element{},
+	}
+}
+
+func (m *Memory) Get(ctx context.Context, key string) (*dto.ConversationLogResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, nil
+	}
+
+	entry := el.Value.(*memoryEntry)
+	if time.Now().After(entry.expiresAt) {
+		m.order.Remove(el)
+		delete(m.entries, key)
+		return nil, nil
+	}
+
+	m.order.MoveToFront(el)
+	response := entry.response
+	return &response, nil
+}
+
+func (m *Memory) Set(ctx context.Context, key string, response *dto.ConversationLogResponse) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := &memoryEntry{
+		key:       key,
+		response:  *response,
+		expiresAt: time.Now().Add(m.ttl),
+	}
+
+	if el, ok := m.entries[key]; ok {
+		el.Value = entry
+		m.order.MoveToFront(el)
+		return nil
+	}
+
+	m.entries[key] = m.order.PushFront(entry)
+
+	for m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryEntry).key)
+	}
+
+	return nil
+}
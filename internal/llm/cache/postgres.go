@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/shanto-323/axis/internal/model/dto"
+)
+
+// Postgres keeps entries in the database so they survive restarts and are
+// shared between instances.
+type Postgres struct {
+	store      Store
+	ttl        time.Duration
+	maxEntries int
+}
+
+func NewPostgres(store Store, ttl time.Duration, maxEntries int) *Postgres {
+	return &Postgres{
+		store:      store,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+	}
+}
+
+func (p *Postgres) Get(ctx context.Context, key string) (*dto.ConversationLogResponse, error) {
+	return p.store.GetCachedResponse(ctx, key)
+}
+
+func (p *Postgres) Set(ctx context.Context, key string, response *dto.ConversationLogResponse) error {
+	return p.store.SaveCachedResponse(ctx, key, response, time.Now().Add(p.ttl), p.maxEntries)
+}
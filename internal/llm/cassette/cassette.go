@@ -0,0 +1,267 @@
+// Package cassette records provider HTTP traffic to a file and replays it,
+// so tests can run against real model answers without network access.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/openai/openai-go/v3/option"
+)
+
+const (
+	ModeRecord = "record"
+	ModeReplay = "replay"
+)
+
+const redacted = "[REDACTED]"
+
+// ErrUnmatched is returned in replay mode for requests the cassette has no
+// recording of.
+var ErrUnmatched = errors.New("no recorded interaction matches the request")
+
+// sensitiveHeaders are redacted in recorded requests. Set-Cookie is
+// dropped from responses.
+var sensitiveHeaders = []string{"Authorization", "Api-Key", "X-Api-Key"}
+
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette is a recording of provider interactions, kept in a JSON file.
+type Cassette struct {
+	path   string
+	mode   string
+	apiKey string
+
+	mu           sync.Mutex
+	Interactions []Interaction `json:"interactions"`
+	used         []bool
+}
+
+// New opens a cassette. Recording starts an empty cassette that replaces
+// the file; replaying requires the file.
+func New(path, mode, apiKey string) (*Cassette, error) {
+	c := &Cassette{path: path, mode: mode, apiKey: apiKey}
+
+	switch mode {
+	case ModeRecord:
+		return c, nil
+	case ModeReplay:
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(b, c); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+		}
+		c.used = make([]bool, len(c.Interactions))
+		return c, nil
+	default:
+		return nil, fmt.Errorf("unsupported cassette mode %s", mode)
+	}
+}
+
+// Option plugs the cassette into an OpenAI client.
+func (c *Cassette) Option() option.RequestOption {
+	return option.WithMiddleware(c.Middleware)
+}
+
+func (c *Cassette) Middleware(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.mode == ModeReplay {
+		return c.replay(req, body)
+	}
+	return c.record(req, body, next)
+}
+
+func (c *Cassette) record(req *http.Request, body []byte, next option.MiddlewareNext) (*http.Response, error) {
+	resp, err := next(req)
+	if err != nil {
+		return resp, err
+	}
+
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    c.redact(req.URL.String()),
+			Header: c.redactHeader(req.Header),
+			Body:   c.redact(string(body)),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+		},
+	}
+	interaction.Response.Header.Del("Set-Cookie")
+
+	// Streamed answers are saved once the client has read them, so the
+	// deltas still reach it as they arrive.
+	resp.Body = &recordingBody{
+		ReadCloser: resp.Body,
+		done: func(b []byte) {
+			interaction.Response.Body = c.redact(string(b))
+			c.save(interaction)
+		},
+	}
+
+	return resp, nil
+}
+
+func (c *Cassette) save(interaction Interaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Interactions = append(c.Interactions, interaction)
+
+	// A failed write shows up as a missing interaction on replay.
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(c.path, b, 0o644)
+}
+
+// replay serves the first unused matching interaction, or the last
+// matching one once all are used, so retried requests replay as well.
+func (c *Cassette) replay(req *http.Request, body []byte) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	url := c.redact(req.URL.String())
+	key := canonicalBody(c.redact(string(body)))
+
+	match := -1
+	for i, interaction := range c.Interactions {
+		if interaction.Request.Method != req.Method || interaction.Request.URL != url {
+			continue
+		}
+		if canonicalBody(interaction.Request.Body) != key {
+			continue
+		}
+
+		match = i
+		if !c.used[i] {
+			break
+		}
+	}
+
+	if match < 0 {
+		return nil, fmt.Errorf("cassette %s: %w: %s %s %s", c.path, ErrUnmatched, req.Method, url, body)
+	}
+	c.used[match] = true
+
+	recorded := c.Interactions[match].Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (c *Cassette) redact(s string) string {
+	if c.apiKey == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, c.apiKey, redacted)
+}
+
+func (c *Cassette) redactHeader(header http.Header) http.Header {
+	h := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if h.Get(name) != "" {
+			h.Set(name, redacted)
+		}
+	}
+	for name, values := range h {
+		for i, v := range values {
+			values[i] = c.redact(v)
+		}
+		h[name] = values
+	}
+	return h
+}
+
+// readBody reads the request body and puts it back for the next handler.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+// canonicalBody makes JSON bodies comparable regardless of key order and
+// whitespace.
+func canonicalBody(body string) string {
+	var v any
+	if err := json.Unmarshal([]byte(body), &v); err != nil {
+		return body
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return string(b)
+}
+
+// recordingBody keeps a copy of everything read and hands it to done once
+// the body is exhausted or closed.
+type recordingBody struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	once sync.Once
+	done func([]byte)
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if err == io.EOF {
+		b.once.Do(func() { b.done(b.buf.Bytes()) })
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	b.once.Do(func() { b.done(b.buf.Bytes()) })
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrorKind classifies a failed upstream call.
+type ErrorKind string
+
+const (
+	ErrorKindRateLimited ErrorKind = "rate_limited"
+	ErrorKindTimeout     ErrorKind = "timeout"
+	ErrorKindServer      ErrorKind = "server_error"
+	ErrorKindBadRequest  ErrorKind = "bad_request"
+	ErrorKindCanceled    ErrorKind = "canceled"
+	ErrorKindUnavailable ErrorKind = "unavailable"
+)
+
+// ProviderError wraps a failed upstream call. StatusCode is zero when the
+// request never got a response, e.g. on timeouts or connection errors.
+type ProviderError struct {
+	Provider   string
+	StatusCode int
+	Kind       ErrorKind
+	// RetryAfter is the delay the provider asked for, zero if none.
+	RetryAfter time.Duration
+	// Message is the provider's own explanation, empty if it gave none.
+	Message string
+	Err     error
+}
+
+func NewProviderError(provider string, statusCode int, err error) *ProviderError {
+	return &ProviderError{
+		Provider:   provider,
+		StatusCode: statusCode,
+		Kind:       classify(statusCode, err),
+		Err:        err,
+	}
+}
+
+func classify(statusCode int, err error) ErrorKind {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return ErrorKindRateLimited
+	case statusCode == http.StatusRequestTimeout, statusCode == http.StatusGatewayTimeout:
+		return ErrorKindTimeout
+	case statusCode >= http.StatusInternalServerError:
+		return ErrorKindServer
+	case statusCode >= http.StatusBadRequest:
+		return ErrorKindBadRequest
+	case errors.Is(err, context.Canceled):
+		return ErrorKindCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorKindTimeout
+	default:
+		// No response at all: connection refused, reset, DNS failure.
+		return ErrorKindUnavailable
+	}
+}
+
+func (e *ProviderError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("provider %s: %s (status %d): %v", e.Provider, e.Kind, e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("provider %s: %s: %v", e.Provider, e.Kind, e.Err)
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the same request may succeed on another attempt
+// or another model: rate limits, upstream server errors and timeouts.
+func (e *ProviderError) Retryable() bool {
+	switch e.Kind {
+	case ErrorKindRateLimited, ErrorKindTimeout, ErrorKindServer, ErrorKindUnavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRetryable reports whether err is a retryable ProviderError.
+func IsRetryable(err error) bool {
+	var providerErr *ProviderError
+	return errors.As(err, &providerErr) && providerErr.Retryable()
+}
+
+// ErrCircuitOpen is returned without calling the provider while a model's
+// circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
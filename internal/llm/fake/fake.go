@@ -0,0 +1,283 @@
+// Package fake is a scripted, in-process LLM provider, so the server can
+// run and be tested without network access or an API key.
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shanto-323/axis/config"
+	"github.com/shanto-323/axis/internal/llm"
+	"github.com/shanto-323/axis/internal/model"
+	"github.com/shanto-323/axis/internal/model/dto"
+)
+
+// Fake answers from a Script. Without a script it echoes the last message.
+type Fake struct {
+	name    string
+	logger  *zerolog.Logger
+	timeout time.Duration
+	latency time.Duration
+	script  *Script
+
+	mu    sync.Mutex
+	uses  map[int]int
+	calls int
+}
+
+func New(name string, cfg config.ProviderConfig, log *zerolog.Logger) (*Fake, error) {
+	script := &Script{}
+	if cfg.Script != "" {
+		s, err := LoadScript(cfg.Script)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", name, err)
+		}
+		script = s
+	}
+
+	latency := cfg.Latency
+	if script.Latency > 0 {
+		latency = time.Duration(script.Latency)
+	}
+
+	return &Fake{
+		name:    name,
+		logger:  log,
+		timeout: cfg.Timeout,
+		latency: latency,
+		script:  script,
+		uses:    map[int]int{},
+	}, nil
+}
+
+func (f *Fake) Complete(ctx context.Context, request *llm.Request) (*llm.Completion, error) {
+	ctx, cancel := f.withTimeout(ctx)
+	defer cancel()
+
+	rule, completion := f.answer(request)
+
+	if err := f.wait(ctx, f.ruleLatency(rule)); err != nil {
+		return nil, err
+	}
+
+	if rule != nil && rule.Error != "" {
+		return nil, f.injectedError(rule)
+	}
+
+	return completion, nil
+}
+
+func (f *Fake) CompleteStream(ctx context.Context, request *llm.Request, onDelta llm.StreamFunc) (*llm.Completion, error) {
+	ctx, cancel := f.withTimeout(ctx)
+	defer cancel()
+
+	rule, completion := f.answer(request)
+
+	if err := f.wait(ctx, f.ruleLatency(rule)); err != nil {
+		return nil, err
+	}
+
+	failAfter := -1
+	if rule != nil && rule.Error != "" {
+		failAfter = rule.FailAfter
+	}
+
+	for i, chunk := range chunks(completion.Content, f.script.ChunkSize) {
+		if i == failAfter {
+			return nil, f.injectedError(rule)
+		}
+		if i > 0 {
+			if err := f.wait(ctx, time.Duration(f.script.ChunkDelay)); err != nil {
+				return nil, err
+			}
+		}
+		if err := onDelta(chunk); err != nil {
+			return nil, err
+		}
+	}
+
+	if failAfter >= 0 {
+		return nil, f.injectedError(rule)
+	}
+
+	return completion, nil
+}
+
+// answer picks the rule for a request and builds the completion it
+// describes. rule is nil when the message is echoed.
+func (f *Fake) answer(request *llm.Request) (*Rule, *llm.Completion) {
+	last := ""
+	if len(request.Messages) > 0 {
+		last = request.Messages[len(request.Messages)-1].Content
+	}
+
+	f.mu.Lock()
+	f.calls++
+	id := fmt.Sprintf("fake-%s-%d", f.name, f.calls)
+
+	var rule *Rule
+	for i := range f.script.Rules {
+		r := &f.script.Rules[i]
+		if r.Model != "" && r.Model != request.Model {
+			continue
+		}
+		if r.Times > 0 && f.uses[i] >= r.Times {
+			continue
+		}
+		if !r.re.MatchString(last) {
+			continue
+		}
+
+		f.uses[i]++
+		rule = r
+		break
+	}
+	f.mu.Unlock()
+
+	completion := &llm.Completion{
+		Content:      last,
+		FinishReason: "stop",
+		ResponseID:   id,
+	}
+
+	if rule != nil && (rule.Response != "" || len(rule.ToolCalls) > 0) {
+		completion.Content = rule.Response
+	}
+	if rule != nil {
+		completion.Reasoning = rule.Reasoning
+	}
+
+	// The registry disables tools on the last turn; answer with text then.
+	if rule != nil && len(rule.ToolCalls) > 0 && len(request.Tools) > 0 && !request.DisableTools {
+		completion.FinishReason = "tool_calls"
+		for i, call := range rule.ToolCalls {
+			completion.ToolCalls = append(completion.ToolCalls, model.ToolCall{
+				ID:        fmt.Sprintf("%s-call-%d", id, i),
+				Name:      call.Name,
+				Arguments: call.Arguments,
+			})
+		}
+	}
+
+	completion.PromptTokens = promptTokens(request.Messages)
+	completion.ReasoningTokens = countTokens(completion.Reasoning)
+	completion.CompletionTokens = countTokens(completion.Content) + completion.ReasoningTokens
+	completion.TotalTokens = completion.PromptTokens + completion.CompletionTokens
+
+	return rule, completion
+}
+
+func (f *Fake) ruleLatency(rule *Rule) time.Duration {
+	if rule != nil && rule.Latency != nil {
+		return time.Duration(*rule.Latency)
+	}
+	return f.latency
+}
+
+// wait sleeps for d unless the context ends first.
+func (f *Fake) wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return llm.NewProviderError(f.name, 0, ctx.Err())
+	}
+}
+
+func (f *Fake) injectedError(rule *Rule) error {
+	statusCode, cause, _ := parseError(rule.Error)
+
+	f.logger.Debug().
+		Str("event", "llm-fake").
+		Str("provider", f.name).
+		Str("error", rule.Error).
+		Msg("injecting error")
+
+	providerErr := llm.NewProviderError(f.name, statusCode, cause)
+	providerErr.RetryAfter = time.Duration(rule.RetryAfter)
+	return providerErr
+}
+
+// parseError turns the error of a rule into a status code and cause. ok is
+// false for unknown errors.
+func parseError(spec string) (statusCode int, cause error, ok bool) {
+	switch spec {
+	case "timeout":
+		return 0, context.DeadlineExceeded, true
+	case "unavailable":
+		return 0, errors.New("connection refused"), true
+	}
+
+	statusCode, err := strconv.Atoi(spec)
+	if err != nil || statusCode < 400 || statusCode > 599 {
+		return 0, nil, false
+	}
+	return statusCode, errors.New(http.StatusText(statusCode)), true
+}
+
+// chunks splits content after every size words, keeping the whitespace so
+// the chunks join back to content.
+func chunks(content string, size int) []string {
+	if size <= 0 {
+		size = 1
+	}
+
+	var out []string
+	var chunk strings.Builder
+	words := 0
+	inWord := false
+
+	for _, r := range content {
+		isSpace := r == ' ' || r == '\n' || r == '\t'
+		if !isSpace && !inWord {
+			if words == size {
+				out = append(out, chunk.String())
+				chunk.Reset()
+				words = 0
+			}
+			words++
+		}
+		inWord = !isSpace
+		chunk.WriteRune(r)
+	}
+
+	if chunk.Len() > 0 {
+		out = append(out, chunk.String())
+	}
+	return out
+}
+
+// countTokens approximates tokens by words, which keeps usage
+// deterministic.
+func countTokens(content string) int {
+	return len(strings.Fields(content))
+}
+
+func promptTokens(messages []dto.ChatMessage) int {
+	total := 0
+	for _, m := range messages {
+		total += countTokens(m.Content)
+	}
+	return total
+}
+
+func (f *Fake) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if f.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, f.timeout)
+}
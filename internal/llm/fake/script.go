@@ -0,0 +1,107 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/shanto-323/axis/internal/model"
+)
+
+// Script scripts the answers of a fake provider. Rules are checked in
+// order against the last message of a request; requests no rule matches
+// get that message echoed back.
+type Script struct {
+	Latency Duration `json:"latency"`
+	// ChunkSize is the number of words per streamed chunk, 1 if unset.
+	ChunkSize  int      `json:"chunk_size"`
+	ChunkDelay Duration `json:"chunk_delay"`
+	Rules      []Rule   `json:"rules"`
+}
+
+// Rule answers matching requests with a canned response, tool calls or an
+// injected error.
+type Rule struct {
+	// Match is a regular expression; empty matches every request.
+	Match string `json:"match"`
+	// Model restricts the rule to one upstream model id.
+	Model string `json:"model"`
+
+	Response  string           `json:"response"`
+	ToolCalls []model.ToolCall `json:"tool_calls"`
+	// Reasoning is returned apart from the response, like providers that
+	// report reasoning in a field of its own.
+	Reasoning string `json:"reasoning"`
+
+	// Error is an HTTP status code such as "429" or "500", "timeout" or
+	// "unavailable".
+	Error      string   `json:"error"`
+	RetryAfter Duration `json:"retry_after"`
+	// FailAfter streams that many chunks before failing with Error.
+	FailAfter int `json:"fail_after"`
+
+	// Latency replaces the script latency for this rule.
+	Latency *Duration `json:"latency"`
+	// Times limits how often the rule applies, 0 means always.
+	Times int `json:"times"`
+
+	re *regexp.Regexp
+}
+
+// Duration reads durations such as "250ms" from JSON.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"250ms\"")
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+	return nil
+}
+
+// LoadScript reads and compiles a script file.
+func LoadScript(path string) (*Script, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fake script: %w", err)
+	}
+
+	script := &Script{}
+	if err := json.Unmarshal(b, script); err != nil {
+		return nil, fmt.Errorf("failed to parse fake script: %w", err)
+	}
+
+	if err := script.compile(); err != nil {
+		return nil, err
+	}
+
+	return script, nil
+}
+
+func (s *Script) compile() error {
+	for i := range s.Rules {
+		r := &s.Rules[i]
+
+		re, err := regexp.Compile(r.Match)
+		if err != nil {
+			return fmt.Errorf("fake script rule %d: %w", i, err)
+		}
+		r.re = re
+
+		if r.Error != "" {
+			if _, _, ok := parseError(r.Error); !ok {
+				return fmt.Errorf("fake script rule %d: error must be an HTTP error status, timeout or unavailable, got %q", i, r.Error)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,549 @@
+// Package jsonschema validates decoded JSON values against the subset of
+// JSON Schema that structured model output needs: type, enum, const,
+// properties, required, additionalProperties, items, min/max items, string
+// length and pattern, numeric bounds, allOf, anyOf, oneOf, not and local
+// $ref. Other keywords are ignored.
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Violation is one way a value fails a schema. Path is a JSON pointer to
+// the offending value, empty for the root.
+type Violation struct {
+	Path    string
+	Message string
+}
+
+type Schema struct {
+	root *node
+}
+
+type node struct {
+	// always is set for the boolean schemas true and false.
+	always *bool
+
+	types     []string
+	enum      []any
+	constant  any
+	hasConst  bool
+	ref       *node
+	refTarget string
+
+	properties           map[string]*node
+	required             []string
+	additionalProperties *node
+
+	items    *node
+	minItems *int
+	maxItems *int
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+
+	allOf []*node
+	anyOf []*node
+	oneOf []*node
+	not   *node
+}
+
+type compiler struct {
+	doc  any
+	refs map[string]*node
+}
+
+// Compile checks a schema and prepares it for validation.
+func Compile(schema map[string]any) (*Schema, error) {
+	c := &compiler{doc: schema, refs: map[string]*node{}}
+
+	root, err := c.compile(schema, "#")
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCycles(root); err != nil {
+		return nil, err
+	}
+
+	return &Schema{root: root}, nil
+}
+
+func (c *compiler) compile(v any, at string) (*node, error) {
+	switch s := v.(type) {
+	case bool:
+		return &node{always: &s}, nil
+	case map[string]any:
+		return c.compileObject(s, at)
+	default:
+		return nil, fmt.Errorf("%s: schema must be an object or a boolean", at)
+	}
+}
+
+func (c *compiler) compileObject(s map[string]any, at string) (*node, error) {
+	n := &node{}
+	var err error
+
+	if ref, ok := s["$ref"]; ok {
+		target, ok := ref.(string)
+		if !ok || !strings.HasPrefix(target, "#") {
+			return nil, fmt.Errorf("%s: only local $ref values are supported", at)
+		}
+		if n.ref, err = c.resolve(target); err != nil {
+			return nil, fmt.Errorf("%s: %w", at, err)
+		}
+		n.refTarget = target
+	}
+
+	if t, ok := s["type"]; ok {
+		switch t := t.(type) {
+		case string:
+			n.types = []string{t}
+		case []any:
+			for _, v := range t {
+				name, ok := v.(string)
+				if !ok {
+					return nil, fmt.Errorf("%s/type: must contain strings", at)
+				}
+				n.types = append(n.types, name)
+			}
+		default:
+			return nil, fmt.Errorf("%s/type: must be a string or an array", at)
+		}
+		for _, name := range n.types {
+			switch name {
+			case "null", "boolean", "object", "array", "number", "integer", "string":
+			default:
+				return nil, fmt.Errorf("%s/type: unknown type %s", at, name)
+			}
+		}
+	}
+
+	if e, ok := s["enum"]; ok {
+		values, ok := e.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s/enum: must be an array", at)
+		}
+		n.enum = values
+	}
+
+	if v, ok := s["const"]; ok {
+		n.constant, n.hasConst = v, true
+	}
+
+	if p, ok := s["properties"]; ok {
+		props, ok := p.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s/properties: must be an object", at)
+		}
+		n.properties = make(map[string]*node, len(props))
+		for name, sub := range props {
+			if n.properties[name], err = c.compile(sub, at+"/properties/"+escape(name)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if r, ok := s["required"]; ok {
+		names, ok := r.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s/required: must be an array", at)
+		}
+		for _, v := range names {
+			name, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s/required: must contain strings", at)
+			}
+			n.required = append(n.required, name)
+		}
+	}
+
+	for keyword, target := range map[string]**node{
+		"additionalProperties": &n.additionalProperties,
+		"items":                &n.items,
+		"not":                  &n.not,
+	} {
+		if sub, ok := s[keyword]; ok {
+			if *target, err = c.compile(sub, at+"/"+keyword); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for keyword, target := range map[string]*[]*node{
+		"allOf": &n.allOf,
+		"anyOf": &n.anyOf,
+		"oneOf": &n.oneOf,
+	} {
+		v, ok := s[keyword]
+		if !ok {
+			continue
+		}
+		subs, ok := v.([]any)
+		if !ok || len(subs) == 0 {
+			return nil, fmt.Errorf("%s/%s: must be a non-empty array", at, keyword)
+		}
+		for i, sub := range subs {
+			compiled, err := c.compile(sub, fmt.Sprintf("%s/%s/%d", at, keyword, i))
+			if err != nil {
+				return nil, err
+			}
+			*target = append(*target, compiled)
+		}
+	}
+
+	for keyword, target := range map[string]**int{
+		"minItems":  &n.minItems,
+		"maxItems":  &n.maxItems,
+		"minLength": &n.minLength,
+		"maxLength": &n.maxLength,
+	} {
+		if v, ok := s[keyword]; ok {
+			f, ok := v.(float64)
+			if !ok || f < 0 || f != math.Trunc(f) {
+				return nil, fmt.Errorf("%s/%s: must be a non-negative integer", at, keyword)
+			}
+			i := int(f)
+			*target = &i
+		}
+	}
+
+	for keyword, target := range map[string]**float64{
+		"minimum":          &n.minimum,
+		"maximum":          &n.maximum,
+		"exclusiveMinimum": &n.exclusiveMinimum,
+		"exclusiveMaximum": &n.exclusiveMaximum,
+	} {
+		if v, ok := s[keyword]; ok {
+			f, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("%s/%s: must be a number", at, keyword)
+			}
+			*target = &f
+		}
+	}
+
+	if p, ok := s["pattern"]; ok {
+		pattern, ok := p.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s/pattern: must be a string", at)
+		}
+		if n.pattern, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("%s/pattern: %w", at, err)
+		}
+	}
+
+	return n, nil
+}
+
+// resolve compiles the schema a local $ref points to. Targets are compiled
+// once, so recursive schemas terminate.
+func (c *compiler) resolve(target string) (*node, error) {
+	if n, ok := c.refs[target]; ok {
+		return n, nil
+	}
+
+	v := c.doc
+	pointer := strings.TrimPrefix(target, "#")
+	if pointer != "" {
+		for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			switch cur := v.(type) {
+			case map[string]any:
+				next, ok := cur[token]
+				if !ok {
+					return nil, fmt.Errorf("unresolvable $ref %s", target)
+				}
+				v = next
+			case []any:
+				i, err := strconv.Atoi(token)
+				if err != nil || i < 0 || i >= len(cur) {
+					return nil, fmt.Errorf("unresolvable $ref %s", target)
+				}
+				v = cur[i]
+			default:
+				return nil, fmt.Errorf("unresolvable $ref %s", target)
+			}
+		}
+	}
+
+	// Register the node before compiling its body to allow cycles.
+	n := &node{}
+	c.refs[target] = n
+
+	compiled, err := c.compile(v, target)
+	if err != nil {
+		return nil, err
+	}
+	*n = *compiled
+
+	return n, nil
+}
+
+// checkCycles rejects $ref cycles that lead back to a schema for the same
+// value without descending into a property or item first. Validating
+// against them would never end.
+func checkCycles(root *node) error {
+	// Every node, found through all edges.
+	var nodes []*node
+	seen := map[*node]bool{}
+	var collect func(n *node)
+	collect = func(n *node) {
+		if n == nil || seen[n] {
+			return
+		}
+		seen[n] = true
+		nodes = append(nodes, n)
+
+		collect(n.ref)
+		collect(n.additionalProperties)
+		collect(n.items)
+		collect(n.not)
+		for _, sub := range n.properties {
+			collect(sub)
+		}
+		for _, subs := range [][]*node{n.allOf, n.anyOf, n.oneOf} {
+			for _, sub := range subs {
+				collect(sub)
+			}
+		}
+	}
+	collect(root)
+
+	// A cycle among the edges that validate the same value. It contains a
+	// $ref, since the other edges only lead into a schema's own body.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[*node]int{}
+	var visit func(n *node, ref string) error
+	visit = func(n *node, ref string) error {
+		switch state[n] {
+		case visiting:
+			return fmt.Errorf("$ref %s: refers back to itself without descending into a property or item", ref)
+		case visited:
+			return nil
+		}
+		state[n] = visiting
+
+		if n.ref != nil {
+			if err := visit(n.ref, n.refTarget); err != nil {
+				return err
+			}
+		}
+		if n.not != nil {
+			if err := visit(n.not, ref); err != nil {
+				return err
+			}
+		}
+		for _, subs := range [][]*node{n.allOf, n.anyOf, n.oneOf} {
+			for _, sub := range subs {
+				if err := visit(sub, ref); err != nil {
+					return err
+				}
+			}
+		}
+
+		state[n] = visited
+		return nil
+	}
+
+	for _, n := range nodes {
+		if err := visit(n, n.refTarget); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks a value decoded by encoding/json.
+func (s *Schema) Validate(v any) []Violation {
+	var violations []Violation
+	s.root.validate(v, "", &violations)
+	return violations
+}
+
+func (n *node) validate(v any, path string, out *[]Violation) {
+	add := func(format string, args ...any) {
+		*out = append(*out, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if n.always != nil {
+		if !*n.always {
+			add("no value is allowed here")
+		}
+		return
+	}
+
+	if n.ref != nil {
+		n.ref.validate(v, path, out)
+	}
+
+	if len(n.types) > 0 && !matchesType(n.types, v) {
+		add("must be of type %s, got %s", strings.Join(n.types, " or "), typeOf(v))
+		return
+	}
+
+	if n.enum != nil {
+		found := false
+		for _, e := range n.enum {
+			if reflect.DeepEqual(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			add("must be one of the enumerated values")
+		}
+	}
+
+	if n.hasConst && !reflect.DeepEqual(n.constant, v) {
+		add("must equal the constant value")
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		for _, name := range n.required {
+			if _, ok := val[name]; !ok {
+				add("missing required property %s", name)
+			}
+		}
+
+		names := make([]string, 0, len(val))
+		for name := range val {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			child := path + "/" + escape(name)
+			if sub, ok := n.properties[name]; ok {
+				sub.validate(val[name], child, out)
+			} else if n.additionalProperties != nil {
+				if n.additionalProperties.always != nil && !*n.additionalProperties.always {
+					add("property %s is not allowed", name)
+					continue
+				}
+				n.additionalProperties.validate(val[name], child, out)
+			}
+		}
+
+	case []any:
+		if n.minItems != nil && len(val) < *n.minItems {
+			add("must have at least %d items", *n.minItems)
+		}
+		if n.maxItems != nil && len(val) > *n.maxItems {
+			add("must have at most %d items", *n.maxItems)
+		}
+		if n.items != nil {
+			for i, item := range val {
+				n.items.validate(item, path+"/"+strconv.Itoa(i), out)
+			}
+		}
+
+	case string:
+		length := utf8.RuneCountInString(val)
+		if n.minLength != nil && length < *n.minLength {
+			add("must be at least %d characters", *n.minLength)
+		}
+		if n.maxLength != nil && length > *n.maxLength {
+			add("must be at most %d characters", *n.maxLength)
+		}
+		if n.pattern != nil && !n.pattern.MatchString(val) {
+			add("must match pattern %s", n.pattern)
+		}
+
+	case float64:
+		if n.minimum != nil && val < *n.minimum {
+			add("must be >= %v", *n.minimum)
+		}
+		if n.maximum != nil && val > *n.maximum {
+			add("must be <= %v", *n.maximum)
+		}
+		if n.exclusiveMinimum != nil && val <= *n.exclusiveMinimum {
+			add("must be > %v", *n.exclusiveMinimum)
+		}
+		if n.exclusiveMaximum != nil && val >= *n.exclusiveMaximum {
+			add("must be < %v", *n.exclusiveMaximum)
+		}
+	}
+
+	for _, sub := range n.allOf {
+		sub.validate(v, path, out)
+	}
+
+	if len(n.anyOf) > 0 && countMatches(n.anyOf, v) == 0 {
+		add("must match at least one schema in anyOf")
+	}
+
+	if len(n.oneOf) > 0 {
+		if matches := countMatches(n.oneOf, v); matches != 1 {
+			add("must match exactly one schema in oneOf, matched %d", matches)
+		}
+	}
+
+	if n.not != nil && countMatches([]*node{n.not}, v) == 1 {
+		add("must not match the schema in not")
+	}
+}
+
+func countMatches(nodes []*node, v any) int {
+	matches := 0
+	for _, sub := range nodes {
+		var violations []Violation
+		sub.validate(v, "", &violations)
+		if len(violations) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+func matchesType(types []string, v any) bool {
+	actual := typeOf(v)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func typeOf(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case float64:
+		if val == math.Trunc(val) && !math.IsInf(val, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func escape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
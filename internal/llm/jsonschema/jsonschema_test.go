@@ -0,0 +1,66 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func compileJSON(t *testing.T, schema string) (*Schema, error) {
+	t.Helper()
+
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(schema), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return Compile(doc)
+}
+
+func TestCompileRejectsRefCycles(t *testing.T) {
+	schemas := map[string]string{
+		"root":        `{"$ref": "#"}`,
+		"definition":  `{"$defs": {"a": {"$ref": "#/$defs/a"}}, "$ref": "#/$defs/a"}`,
+		"indirect":    `{"$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"$ref": "#/$defs/a"}}, "$ref": "#/$defs/a"}`,
+		"allOf":       `{"$defs": {"a": {"allOf": [{"$ref": "#/$defs/a"}]}}, "$ref": "#/$defs/a"}`,
+		"not":         `{"not": {"$ref": "#"}}`,
+		"in property": `{"properties": {"x": {"$ref": "#/properties/x"}}}`,
+	}
+
+	for name, schema := range schemas {
+		t.Run(name, func(t *testing.T) {
+			if _, err := compileJSON(t, schema); err == nil {
+				t.Fatalf("Compile(%s) succeeded, want a cycle error", schema)
+			}
+		})
+	}
+}
+
+func TestRecursiveSchema(t *testing.T) {
+	schema, err := compileJSON(t, `{
+		"$defs": {
+			"tree": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"children": {"type": "array", "items": {"$ref": "#/$defs/tree"}}
+				},
+				"required": ["name"]
+			}
+		},
+		"$ref": "#/$defs/tree"
+	}`)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	var valid, invalid any
+	_ = json.Unmarshal([]byte(`{"name": "a", "children": [{"name": "b", "children": []}]}`), &valid)
+	_ = json.Unmarshal([]byte(`{"name": "a", "children": [{"children": []}]}`), &invalid)
+
+	if violations := schema.Validate(valid); len(violations) != 0 {
+		t.Errorf("Validate(valid) = %v, want no violations", violations)
+	}
+	violations := schema.Validate(invalid)
+	if len(violations) != 1 || violations[0].Path != "/children/0" {
+		t.Errorf("Validate(invalid) = %v, want a missing name at /children/0", violations)
+	}
+}
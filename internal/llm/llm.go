@@ -3,12 +3,147 @@ package llm
 import (
 	"context"
 
+	"github.com/shanto-323/axis/internal/model"
 	"github.com/shanto-323/axis/internal/model/dto"
 )
 
-type LLMModels map[string]string
+const (
+	ModalityText  = "text"
+	ModalityImage = "image"
+)
+
+// Availability of a catalog model as last seen by model discovery.
+const (
+	ModelStatusUnknown    = "unknown"
+	ModelStatusAvailable  = "available"
+	ModelStatusDeprecated = "deprecated"
+	ModelStatusMissing    = "missing"
+)
+
+// ModelEntry is a catalog entry: the upstream model id, the provider that
+// serves it and what the model is capable of.
+type ModelEntry struct {
+	Name          string
+	DisplayName   string
+	Provider      string
+	Model         string
+	ContextLength int
+	Modalities    []string
+	Reasoning     bool
+	// ThinkOpened is set when the chat template opens the <think> block.
+	ThinkOpened bool
+	// StructuredOutputs is set when the provider accepts response_format.
+	StructuredOutputs bool
+	// MaxOutputTokens defaults to ContextLength; zero means unknown.
+	MaxOutputTokens int
+	InputPrice      float64
+	OutputPrice     float64
+	Fallbacks       []string
+}
+
+func (m ModelEntry) HasModality(modality string) bool {
+	for _, v := range m.Modalities {
+		if v == modality {
+			return true
+		}
+	}
+	return false
+}
+
+type LLMModels map[string]ModelEntry
 
+// Tool is a Go function a model may call while generating an answer.
+type Tool interface {
+	Name() string
+	Description() string
+	// Parameters is the JSON Schema of the arguments object.
+	Parameters() map[string]any
+	// Call runs the tool with the model's JSON arguments. The result is sent
+	// back to the model as is.
+	Call(ctx context.Context, arguments string) (string, error)
+}
+
+// StreamFunc receives every content delta produced by a streaming completion.
+// Returning an error stops the stream.
+type StreamFunc func(delta string) error
+
+// LLM answers chat requests. A generation that spent tokens but failed,
+// e.g. on output that never matched the response_format, returns its
+// response with status failed along with the error, so its usage can be
+// recorded.
 type LLM interface {
-	GenerateResponse(ctx context.Context, request *dto.ChatRequest) (*dto.ConversationLogResponse, error)
+	GenerateResponse(ctx context.Context, request *dto.ChatRequest, history []dto.ChatMessage) (*dto.ConversationLogResponse, error)
+	GenerateStreamResponse(ctx context.Context, request *dto.ChatRequest, history []dto.ChatMessage, onDelta StreamFunc) (*dto.ConversationLogResponse, error)
 	AvailableModels(ctx context.Context) *[]dto.LLMModel
+	GetModel(name string) (*dto.LLMModel, bool)
+}
+
+// Reloader is implemented by LLM backends whose model catalog can be
+// reloaded at runtime.
+type Reloader interface {
+	Reload() error
+}
+
+// BreakerReporter is implemented by LLM backends that keep per-model
+// circuit breakers.
+type BreakerReporter interface {
+	CircuitBreakers() []dto.CircuitBreakerStatus
+}
+
+// CoalescingReporter is implemented by LLM backends that coalesce identical
+// requests in flight.
+type CoalescingReporter interface {
+	CoalescedCalls() int64
+}
+
+// Request is a single completion call as seen by a provider adapter.
+// Model is the upstream model id, not the catalog alias.
+type Request struct {
+	Model    string
+	Messages []dto.ChatMessage
+	Params   model.GenerationParams
+
+	Tools []Tool
+	// DisableTools keeps the tool definitions but asks the model to answer
+	// without calling them.
+	DisableTools bool
+
+	// ResponseFormat is only set for models with structured output support.
+	ResponseFormat *dto.ResponseFormat
+}
+
+type Completion struct {
+	Content string
+	// Reasoning is the model's reasoning, when the provider returns it
+	// apart from the content.
+	Reasoning string
+
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	FinishReason     string
+	// ReasoningTokens are the part of CompletionTokens spent on reasoning,
+	// zero when the provider does not report them.
+	ReasoningTokens int
+	// ResponseID is the provider's id for the generation.
+	ResponseID string
+
+	ToolCalls []model.ToolCall
+}
+
+// Provider is implemented by every backend adapter.
+type Provider interface {
+	Complete(ctx context.Context, request *Request) (*Completion, error)
+	CompleteStream(ctx context.Context, request *Request, onDelta StreamFunc) (*Completion, error)
+}
+
+// UpstreamModel is a model as listed by a provider.
+type UpstreamModel struct {
+	ID         string
+	Deprecated bool
+}
+
+// ModelLister is implemented by providers that can list the models they serve.
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]UpstreamModel, error)
 }
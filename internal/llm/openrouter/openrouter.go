@@ -2,140 +2,401 @@ package openrouter
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/option"
+	"github.com/openai/openai-go/v3/packages/respjson"
+	"github.com/openai/openai-go/v3/shared"
 	"github.com/rs/zerolog"
 	"github.com/shanto-323/axis/config"
-	"github.com/shanto-323/axis/internal/errs"
 	"github.com/shanto-323/axis/internal/llm"
+	"github.com/shanto-323/axis/internal/llm/cassette"
+	"github.com/shanto-323/axis/internal/model"
 	"github.com/shanto-323/axis/internal/model/dto"
-	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/trace"
 )
 
+// Openrouter talks to any OpenAI-compatible chat completions API,
+// OpenRouter being the default one.
 type Openrouter struct {
-	logger *zerolog.Logger
-	config *config.Config
-	tracer trace.Tracer
-
-	llmModels llm.LLMModels
+	name    string
+	logger  *zerolog.Logger
+	timeout time.Duration
+	client  openai.Client
 }
 
-func NewOpenrouter(cfg *config.Config, log *zerolog.Logger, tracer trace.Tracer) *Openrouter {
-	// All available model
-	models := map[string]string{
-		"openai/gpt-120b": "openai/gpt-oss-120b:free",
-		"llama-70b":       "meta-llama/llama-3.3-70b-instruct:free",
-		"nemotron-30b":    "nvidia/nemotron-3-nano-30b-a3b:free",
-		"nemotron-12b":    "nvidia/nemotron-nano-12b-v2-vl:free",
-		"qwen3":           "qwen/qwen3-coder:free",
-		"allenai-32b":     "allenai/olmo-3.1-32b-think:free",
-		"xiaomi-flash":    "xiaomi/mimo-v2-flash:free",
-		"mistralai":       "mistralai/devstral-2512:free",
-		"deepseek-nex":    "nex-agi/deepseek-v3.1-nex-n1:free",
-		"tngtech":         "tngtech/tng-r1t-chimera:free",
-		"kat-coder":       "kwaipilot/kat-coder-pro:free",
+func NewOpenrouter(name string, cfg config.ProviderConfig, log *zerolog.Logger) (*Openrouter, error) {
+	opts := []option.RequestOption{
+		option.WithBaseURL(cfg.BaseURL),
+		option.WithAPIKey(cfg.ApiKey),
+		// Retries are handled by the registry so they can honor circuit
+		// breakers and fallbacks.
+		option.WithMaxRetries(0),
+	}
+	for k, v := range cfg.Headers {
+		opts = append(opts, option.WithHeader(k, v))
+	}
+
+	if cfg.CassetteMode != "" {
+		c, err := cassette.New(cfg.Cassette, cfg.CassetteMode, cfg.ApiKey)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", name, err)
+		}
+		opts = append(opts, c.Option())
+
+		log.Warn().
+			Str("provider", name).
+			Str("cassette", cfg.Cassette).
+			Str("mode", cfg.CassetteMode).
+			Msg("provider traffic goes through a cassette")
 	}
 
 	return &Openrouter{
-		logger:    log,
-		config:    cfg,
-		tracer:    tracer,
-		llmModels: models,
+		name:    name,
+		logger:  log,
+		timeout: cfg.Timeout,
+		client:  openai.NewClient(opts...),
+	}, nil
+}
+
+func (o *Openrouter) Complete(ctx context.Context, request *llm.Request) (*llm.Completion, error) {
+	ctx, cancel := o.withTimeout(ctx)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := o.client.Chat.Completions.New(ctx, newParams(request))
+	if err != nil {
+		return nil, o.wrapError(err)
+	}
+	if len(resp.Choices) == 0 {
+		// Seen when the upstream model is overloaded; another attempt or
+		// model may well answer.
+		providerErr := llm.NewProviderError(o.name, 0, errors.New("response has no choices"))
+		providerErr.Kind = llm.ErrorKindServer
+		return nil, providerErr
+	}
+
+	exicutionTime := int(time.Since(start).Seconds())
+
+	o.logger.Info().
+		Str("event", "llm-response").
+		Str("provider", o.name).
+		Int("time", exicutionTime).
+		Msg("successful")
+
+	completion := &llm.Completion{
+		Content:          resp.Choices[0].Message.Content,
+		Reasoning:        reasoningText(resp.Choices[0].Message.JSON.ExtraFields),
+		PromptTokens:     int(resp.Usage.PromptTokens),
+		CompletionTokens: int(resp.Usage.CompletionTokens),
+		TotalTokens:      int(resp.Usage.TotalTokens),
+		ReasoningTokens:  int(resp.Usage.CompletionTokensDetails.ReasoningTokens),
+		FinishReason:     resp.Choices[0].FinishReason,
+		ResponseID:       resp.ID,
+	}
+
+	for _, call := range resp.Choices[0].Message.ToolCalls {
+		if call.Type != "function" {
+			continue
+		}
+		completion.ToolCalls = append(completion.ToolCalls, model.ToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: call.Function.Arguments,
+		})
 	}
+
+	return completion, nil
 }
 
-func (o *Openrouter) AvailableModels(ctx context.Context) *[]dto.LLMModel {
-	_, span := o.tracer.Start(ctx, "event.models")
-	defer span.End()
+func (o *Openrouter) CompleteStream(ctx context.Context, request *llm.Request, onDelta llm.StreamFunc) (*llm.Completion, error) {
+	ctx, cancel := o.withTimeout(ctx)
+	defer cancel()
+
+	params := newParams(request)
+	params.StreamOptions = openai.ChatCompletionStreamOptionsParam{
+		IncludeUsage: openai.Bool(true),
+	}
+
+	stream := o.client.Chat.Completions.NewStreaming(ctx, params)
+	defer stream.Close()
+
+	completion := &llm.Completion{}
+
+	// Tool calls arrive in pieces keyed by their index in the message.
+	toolCalls := map[int64]*model.ToolCall{}
+	var toolOrder []int64
+
+	var content, reasoning strings.Builder
+	for stream.Next() {
+		chunk := stream.Current()
+		completion.ResponseID = chunk.ID
+
+		// With include_usage the last chunk carries usage and no choices.
+		if chunk.Usage.TotalTokens > 0 {
+			completion.PromptTokens = int(chunk.Usage.PromptTokens)
+			completion.CompletionTokens = int(chunk.Usage.CompletionTokens)
+			completion.TotalTokens = int(chunk.Usage.TotalTokens)
+			completion.ReasoningTokens = int(chunk.Usage.CompletionTokensDetails.ReasoningTokens)
+		}
+
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		if reason := chunk.Choices[0].FinishReason; reason != "" {
+			completion.FinishReason = reason
+		}
+
+		for _, d := range chunk.Choices[0].Delta.ToolCalls {
+			call, ok := toolCalls[d.Index]
+			if !ok {
+				call = &model.ToolCall{}
+				toolCalls[d.Index] = call
+				toolOrder = append(toolOrder, d.Index)
+			}
+			if d.ID != "" {
+				call.ID = d.ID
+			}
+			call.Name += d.Function.Name
+			call.Arguments += d.Function.Arguments
+		}
+
+		// Reasoning is kept for the final answer rather than streamed.
+		reasoning.WriteString(reasoningText(chunk.Choices[0].Delta.JSON.ExtraFields))
+
+		delta := chunk.Choices[0].Delta.Content
+		if delta == "" {
+			continue
+		}
 
-	models := []dto.LLMModel{}
-	for k, v := range o.llmModels {
-		model := dto.LLMModel{
-			Name:  k,
-			Model: v,
+		content.WriteString(delta)
+		if err := onDelta(delta); err != nil {
+			return nil, err
 		}
-		models = append(models, model)
 	}
 
-	span.SetAttributes(
-		attribute.String("get all models", "success"),
-	)
+	if err := stream.Err(); err != nil {
+		return nil, o.wrapError(err)
+	}
+
+	completion.Content = content.String()
+	completion.Reasoning = reasoning.String()
+	for _, i := range toolOrder {
+		completion.ToolCalls = append(completion.ToolCalls, *toolCalls[i])
+	}
 
-	return &models
+	return completion, nil
 }
 
-func (o *Openrouter) GenerateResponse(ctx context.Context, request *dto.ChatRequest) (*dto.ConversationLogResponse, error) {
-	ctx, span := o.tracer.Start(ctx, "event.llm_response")
-	defer span.End()
+// reasoningFields are the message fields reasoning is returned in:
+// reasoning by OpenRouter, reasoning_content by DeepSeek and vLLM.
+var reasoningFields = []string{"reasoning", "reasoning_content"}
 
-	startTime := time.Now()
+func reasoningText(fields map[string]respjson.Field) string {
+	for _, name := range reasoningFields {
+		var text string
+		if err := json.Unmarshal([]byte(fields[name].Raw()), &text); err == nil && text != "" {
+			return text
+		}
+	}
+	return ""
+}
 
-	model, ok := o.llmModels[request.Model]
-	if !ok {
-		code := "INVALID_MODEL_NAME"
-		return nil, errs.NewNotFoundError("no such model found :"+request.Model, true, &code)
+func newParams(request *llm.Request) openai.ChatCompletionNewParams {
+	params := openai.ChatCompletionNewParams{
+		Messages: buildMessages(request.Messages),
+		Model:    request.Model,
 	}
 
-	responseString, _, err := o.response(
-		ctx,
-		request.Message,
-		model,
-	)
+	p := request.Params
+	if p.Temperature != nil {
+		params.Temperature = openai.Float(*p.Temperature)
+	}
+	if p.TopP != nil {
+		params.TopP = openai.Float(*p.TopP)
+	}
+	if p.MaxTokens != nil {
+		// max_tokens is understood by more OpenAI-compatible servers than
+		// max_completion_tokens.
+		params.MaxTokens = openai.Int(int64(*p.MaxTokens))
+	}
+	if len(p.Stop) > 0 {
+		params.Stop = openai.ChatCompletionNewParamsStopUnion{OfStringArray: p.Stop}
+	}
+	if p.Seed != nil {
+		params.Seed = openai.Int(*p.Seed)
+	}
+	if p.PresencePenalty != nil {
+		params.PresencePenalty = openai.Float(*p.PresencePenalty)
+	}
+	if p.FrequencyPenalty != nil {
+		params.FrequencyPenalty = openai.Float(*p.FrequencyPenalty)
+	}
+	if p.ReasoningEffort != "" {
+		params.ReasoningEffort = shared.ReasoningEffort(p.ReasoningEffort)
+	}
+
+	if f := request.ResponseFormat; f.IsJSON() {
+		if f.Type == dto.ResponseFormatJSONObject {
+			params.ResponseFormat.OfJSONObject = &shared.ResponseFormatJSONObjectParam{}
+		} else {
+			params.ResponseFormat.OfJSONSchema = &shared.ResponseFormatJSONSchemaParam{
+				JSONSchema: shared.ResponseFormatJSONSchemaJSONSchemaParam{
+					Name:   f.JSONSchema.Name,
+					Schema: f.JSONSchema.Schema,
+					Strict: openai.Bool(f.JSONSchema.Strict),
+				},
+			}
+			if f.JSONSchema.Description != "" {
+				params.ResponseFormat.OfJSONSchema.JSONSchema.Description = openai.String(f.JSONSchema.Description)
+			}
+		}
+	}
+
+	for _, t := range request.Tools {
+		params.Tools = append(params.Tools, openai.ChatCompletionFunctionTool(shared.FunctionDefinitionParam{
+			Name:        t.Name(),
+			Description: openai.String(t.Description()),
+			Parameters:  shared.FunctionParameters(t.Parameters()),
+		}))
+	}
+	if len(request.Tools) > 0 && request.DisableTools {
+		params.ToolChoice = openai.ChatCompletionToolChoiceOptionUnionParam{
+			OfAuto: openai.String("none"),
+		}
+	}
+
+	return params
+}
+
+func (o *Openrouter) ListModels(ctx context.Context) ([]llm.UpstreamModel, error) {
+	ctx, cancel := o.withTimeout(ctx)
+	defer cancel()
+
+	page, err := o.client.Models.List(ctx)
 	if err != nil {
+		return nil, o.wrapError(err)
+	}
+
+	models := make([]llm.UpstreamModel, 0, len(page.Data))
+	for _, m := range page.Data {
+		// OpenRouter announces upcoming removals through expiration_date.
+		expiration := m.JSON.ExtraFields["expiration_date"].Raw()
+		deprecated := expiration != "" && expiration != "null"
+
+		models = append(models, llm.UpstreamModel{
+			ID:         m.ID,
+			Deprecated: deprecated,
+		})
+	}
+
+	return models, nil
+}
+
+func (o *Openrouter) wrapError(err error) error {
+	// A replayed test must not pass on retries or fallbacks.
+	if errors.Is(err, cassette.ErrUnmatched) {
 		o.logger.Error().
 			Err(err).
-			Str("event", "llm-response").
-			Msg("response gen failed")
+			Str("provider", o.name).
+			Msg("request not found in cassette")
 
-		span.RecordError(err)
-		return nil, errs.NewInternalServerError()
+		providerErr := llm.NewProviderError(o.name, 0, err)
+		providerErr.Kind = llm.ErrorKindBadRequest
+		return providerErr
 	}
 
-	totalTime := int(time.Since(startTime).Seconds())
+	var apiErr *openai.Error
+	if !errors.As(err, &apiErr) {
+		return llm.NewProviderError(o.name, 0, err)
+	}
 
-	o.logger.Info().
-		Str("event", "llm-response").
-		Int("time", totalTime).
-		Msg("success")
+	providerErr := llm.NewProviderError(o.name, apiErr.StatusCode, err)
+	providerErr.Message = apiErr.Message
+	if apiErr.Response != nil {
+		providerErr.RetryAfter = parseRetryAfter(apiErr.Response.Header.Get("Retry-After"))
+	}
 
-	response := dto.ConversationLogResponse{
-		TextQuery:    request.Message,
-		ResponseText: responseString,
-		TimeTaken:    totalTime,
+	return providerErr
+}
+
+// parseRetryAfter accepts both forms of the header: delay seconds and an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
 	}
 
-	response.LLMModelName = request.Model
-	response.Timestamp = time.Now()
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
 
-	return &response, nil
-}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
 
-func (o *Openrouter) response(ctx context.Context, query string, model string) (string, int, error) {
-	client := openai.NewClient(
-		option.WithBaseURL(o.config.AiManage.Provider),
-		option.WithAPIKey(o.config.AiManage.ApiKey),
-	)
+	return 0
+}
 
-	start := time.Now()
-	resp, err := client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
-		Messages: []openai.ChatCompletionMessageParamUnion{
-			openai.UserMessage(query),
-		},
-		Model: model,
-	})
-	if err != nil {
-		return "", 0, err
+func (o *Openrouter) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
+	return context.WithTimeout(ctx, o.timeout)
+}
 
-	exicutionTime := int(time.Since(start).Seconds())
+func buildMessages(messages []dto.ChatMessage) []openai.ChatCompletionMessageParamUnion {
+	params := make([]openai.ChatCompletionMessageParamUnion, 0, len(messages))
+	for _, m := range messages {
+		switch m.Role {
+		case dto.RoleSystem:
+			params = append(params, openai.SystemMessage(m.Content))
+		case dto.RoleAssistant:
+			if len(m.ToolCalls) == 0 {
+				params = append(params, openai.AssistantMessage(m.Content))
+				continue
+			}
 
-	o.logger.Info().
-		Str("event", "llm-response").
-		Int("time", exicutionTime).
-		Msg("successful")
+			assistant := openai.ChatCompletionAssistantMessageParam{}
+			if m.Content != "" {
+				assistant.Content.OfString = openai.String(m.Content)
+			}
+			for _, call := range m.ToolCalls {
+				assistant.ToolCalls = append(assistant.ToolCalls, openai.ChatCompletionMessageToolCallUnionParam{
+					OfFunction: &openai.ChatCompletionMessageFunctionToolCallParam{
+						ID: call.ID,
+						Function: openai.ChatCompletionMessageFunctionToolCallFunctionParam{
+							Name:      call.Name,
+							Arguments: call.Arguments,
+						},
+					},
+				})
+			}
+			params = append(params, openai.ChatCompletionMessageParamUnion{OfAssistant: &assistant})
+		case dto.RoleTool:
+			params = append(params, openai.ToolMessage(m.Content, m.ToolCallID))
+		default:
+			if len(m.Images) == 0 {
+				params = append(params, openai.UserMessage(m.Content))
+				continue
+			}
 
-	return resp.Choices[0].Message.Content, exicutionTime, nil
+			parts := []openai.ChatCompletionContentPartUnionParam{openai.TextContentPart(m.Content)}
+			for _, img := range m.Images {
+				parts = append(parts, openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{
+					URL: img.DataURL(),
+				}))
+			}
+			params = append(params, openai.UserMessage(parts))
+		}
+	}
+	return params
 }
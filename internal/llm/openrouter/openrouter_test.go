@@ -0,0 +1,159 @@
+package openrouter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/shanto-323/axis/config"
+	"github.com/shanto-323/axis/internal/llm"
+	"github.com/shanto-323/axis/internal/llm/cassette"
+	"github.com/shanto-323/axis/internal/model/dto"
+)
+
+// upstream stands in for an OpenAI-compatible API, answering each path
+// with a fixed JSON body.
+func upstream(t *testing.T, bodies map[string]string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := bodies[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func newProvider(t *testing.T, cfg config.ProviderConfig) *Openrouter {
+	t.Helper()
+
+	log := zerolog.Nop()
+	o, err := NewOpenrouter("openrouter", cfg, &log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return o
+}
+
+func TestListModels(t *testing.T) {
+	srv := upstream(t, map[string]string{
+		"/models": `{"object": "list", "data": [
+			{"id": "vendor/current", "object": "model", "created": 1, "owned_by": "vendor"},
+			{"id": "vendor/no-expiry", "object": "model", "created": 1, "owned_by": "vendor", "expiration_date": null},
+			{"id": "vendor/leaving", "object": "model", "created": 1, "owned_by": "vendor", "expiration_date": "2026-12-01"}
+		]}`,
+	})
+
+	models, err := newProvider(t, config.ProviderConfig{BaseURL: srv.URL}).ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListModels: %v", err)
+	}
+
+	want := map[string]bool{
+		"vendor/current":   false,
+		"vendor/no-expiry": false,
+		"vendor/leaving":   true,
+	}
+	if len(models) != len(want) {
+		t.Fatalf("ListModels returned %d models, want %d", len(models), len(want))
+	}
+	for _, m := range models {
+		deprecated, ok := want[m.ID]
+		if !ok {
+			t.Errorf("unexpected model %s", m.ID)
+			continue
+		}
+		if m.Deprecated != deprecated {
+			t.Errorf("%s: Deprecated = %v, want %v", m.ID, m.Deprecated, deprecated)
+		}
+	}
+}
+
+const completionBody = `{
+	"id": "gen-1",
+	"object": "chat.completion",
+	"created": 1,
+	"model": "vendor/current",
+	"choices": [{"index": 0, "finish_reason": "stop", "message": {"role": "assistant", "content": "Hello there."}}],
+	"usage": {"prompt_tokens": 5, "completion_tokens": 3, "total_tokens": 8}
+}`
+
+// TestCassetteReplay records a completion and replays it once the upstream
+// is gone, with the API key kept out of the file.
+func TestCassetteReplay(t *testing.T) {
+	srv := upstream(t, map[string]string{"/chat/completions": completionBody})
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	request := &llm.Request{
+		Model:    "vendor/current",
+		Messages: []dto.ChatMessage{{Role: dto.RoleUser, Content: "hi"}},
+	}
+
+	recorder := newProvider(t, config.ProviderConfig{
+		BaseURL:      srv.URL,
+		ApiKey:       "secret-key",
+		Cassette:     path,
+		CassetteMode: cassette.ModeRecord,
+	})
+	if _, err := recorder.Complete(context.Background(), request); err != nil {
+		t.Fatalf("recording: %v", err)
+	}
+	srv.Close()
+
+	c, err := cassette.New(path, cassette.ModeReplay, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Interactions) != 1 {
+		t.Fatalf("cassette has %d interactions, want 1", len(c.Interactions))
+	}
+	if b, _ := os.ReadFile(path); strings.Contains(string(b), "secret-key") {
+		t.Error("the cassette contains the API key")
+	}
+
+	player := newProvider(t, config.ProviderConfig{
+		BaseURL:      srv.URL,
+		ApiKey:       "secret-key",
+		Cassette:     path,
+		CassetteMode: cassette.ModeReplay,
+	})
+	completion, err := player.Complete(context.Background(), request)
+	if err != nil {
+		t.Fatalf("replaying: %v", err)
+	}
+	if completion.Content != "Hello there." || completion.TotalTokens != 8 {
+		t.Errorf("replayed completion = %q with %d tokens", completion.Content, completion.TotalTokens)
+	}
+
+	// A request the cassette has not seen fails without retries.
+	request.Messages[0].Content = "something else"
+	_, err = player.Complete(context.Background(), request)
+	if err == nil || llm.IsRetryable(err) {
+		t.Errorf("unmatched request: err = %v, want a non-retryable error", err)
+	}
+}
+
+func TestCompleteWithoutChoicesIsRetryable(t *testing.T) {
+	srv := upstream(t, map[string]string{
+		"/chat/completions": `{"id": "gen-1", "object": "chat.completion", "created": 1, "model": "vendor/current", "choices": []}`,
+	})
+
+	_, err := newProvider(t, config.ProviderConfig{BaseURL: srv.URL}).Complete(context.Background(), &llm.Request{
+		Model:    "vendor/current",
+		Messages: []dto.ChatMessage{{Role: dto.RoleUser, Content: "hi"}},
+	})
+	if !llm.IsRetryable(err) {
+		t.Errorf("err = %v, want a retryable provider error", err)
+	}
+}
@@ -0,0 +1,190 @@
+// Package reasoning separates a model's reasoning from its answer when the
+// model writes both into the content, between <think> tags.
+package reasoning
+
+import (
+	"strings"
+
+	"github.com/shanto-323/axis/internal/llm"
+)
+
+const (
+	openTag  = "<think>"
+	closeTag = "</think>"
+)
+
+// Split returns the answer and the reasoning of content. Besides <think>
+// blocks it handles a closing tag without an opening one, as written by
+// models whose chat template opens the block in the prompt.
+func Split(content string) (answer, reasoning string) {
+	var thoughts []string
+	rest := content
+
+	if end := strings.Index(rest, closeTag); end >= 0 {
+		if start := strings.Index(rest, openTag); start < 0 || start > end {
+			thoughts = append(thoughts, rest[:end])
+			rest = rest[end+len(closeTag):]
+		}
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.Index(rest, openTag)
+		if start < 0 {
+			b.WriteString(rest)
+			break
+		}
+		b.WriteString(rest[:start])
+		rest = rest[start+len(openTag):]
+
+		end := strings.Index(rest, closeTag)
+		if end < 0 {
+			// Cut off while thinking, e.g. by max_tokens.
+			thoughts = append(thoughts, rest)
+			break
+		}
+		thoughts = append(thoughts, rest[:end])
+		rest = rest[end+len(closeTag):]
+	}
+
+	for i, t := range thoughts {
+		thoughts[i] = strings.TrimSpace(t)
+	}
+
+	return strings.TrimSpace(b.String()), strings.TrimSpace(strings.Join(thoughts, "\n\n"))
+}
+
+// Filter passes streamed content on without the parts between <think>
+// tags, which may be split over several deltas. A closing tag without an
+// opening one is only recognized by Split, once the answer is complete,
+// unless the filter was made by NewOpenedFilter.
+type Filter struct {
+	next     llm.StreamFunc
+	thinking bool
+	// pending holds the end of the content seen so far while it could be
+	// the start of a tag.
+	pending string
+	started bool
+
+	// held is the start of the content of a template-opened think block,
+	// kept back while holding until its closing tag or holdLimit bytes.
+	holding   bool
+	held      string
+	holdLimit int
+}
+
+func NewFilter(next llm.StreamFunc) *Filter {
+	return &Filter{next: next}
+}
+
+// NewOpenedFilter returns a Filter for models whose chat template opens the
+// think block in the prompt, so their output starts with reasoning that
+// only a closing tag ends. Output is held back until that tag. Once
+// holdLimit bytes arrived without it, the model is taken to have answered
+// without reasoning and the held output is passed on.
+func NewOpenedFilter(next llm.StreamFunc, holdLimit int) *Filter {
+	return &Filter{next: next, holding: true, holdLimit: holdLimit}
+}
+
+func (f *Filter) Write(delta string) error {
+	if f.holding {
+		// Only the new content, and a tag it may complete, need searching.
+		from := max(len(f.held)-len(closeTag)+1, 0)
+		f.held += delta
+
+		end := strings.Index(f.held[from:], closeTag)
+		if end < 0 && len(f.held) <= f.holdLimit {
+			return nil
+		}
+		if end >= 0 {
+			end += from
+		}
+		delta = f.release(end)
+	}
+
+	return f.filter(delta)
+}
+
+// release stops holding back output and returns what follows the closing
+// tag at end, or all of the held output when end is negative.
+func (f *Filter) release(end int) string {
+	held := f.held
+	f.holding = false
+	f.held = ""
+
+	if end < 0 {
+		return held
+	}
+	return held[end+len(closeTag):]
+}
+
+func (f *Filter) filter(delta string) error {
+	text := f.pending + delta
+	f.pending = ""
+
+	var out strings.Builder
+	for text != "" {
+		tag := openTag
+		if f.thinking {
+			tag = closeTag
+		}
+
+		if i := strings.Index(text, tag); i >= 0 {
+			if !f.thinking {
+				out.WriteString(text[:i])
+			}
+			f.thinking = !f.thinking
+			text = text[i+len(tag):]
+			continue
+		}
+
+		// Keep a possible partial tag for the next delta.
+		keep := partialSuffix(text, tag)
+		if !f.thinking {
+			out.WriteString(text[:len(text)-keep])
+		}
+		f.pending = text[len(text)-keep:]
+		break
+	}
+
+	return f.emit(out.String())
+}
+
+// Flush passes on content held back as a possible tag once the stream ends.
+// Held output without a closing tag is the answer, as for Split.
+func (f *Filter) Flush() error {
+	if f.holding {
+		if err := f.filter(f.release(-1)); err != nil {
+			return err
+		}
+	}
+
+	pending := f.pending
+	f.pending = ""
+	if f.thinking {
+		return nil
+	}
+	return f.emit(pending)
+}
+
+// emit drops the whitespace that usually follows a leading think block.
+func (f *Filter) emit(s string) error {
+	if !f.started {
+		s = strings.TrimLeft(s, " \t\r\n")
+	}
+	if s == "" {
+		return nil
+	}
+	f.started = true
+	return f.next(s)
+}
+
+// partialSuffix is the length of the longest end of s that starts tag.
+func partialSuffix(s, tag string) int {
+	for n := min(len(s), len(tag)-1); n > 0; n-- {
+		if strings.HasSuffix(s, tag[:n]) {
+			return n
+		}
+	}
+	return 0
+}
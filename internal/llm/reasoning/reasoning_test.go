@@ -0,0 +1,66 @@
+package reasoning
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOpenedFilter(t *testing.T) {
+	for name, tc := range map[string]struct {
+		deltas    []string
+		holdLimit int
+		want      string
+	}{
+		"closing tag split over deltas": {
+			deltas:    []string{"Two and ", "two.</th", "ink>\n\nFour."},
+			holdLimit: 100,
+			want:      "Four.",
+		},
+		"no reasoning within the limit": {
+			deltas:    []string{"Four, ", "as two ", "and two."},
+			holdLimit: 8,
+			want:      "Four, as two and two.",
+		},
+		"no reasoning before the end": {
+			deltas:    []string{"Four."},
+			holdLimit: 100,
+			want:      "Four.",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var out strings.Builder
+			f := NewOpenedFilter(func(delta string) error {
+				out.WriteString(delta)
+				return nil
+			}, tc.holdLimit)
+
+			for _, d := range tc.deltas {
+				if err := f.Write(d); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := f.Flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			if out.String() != tc.want {
+				t.Errorf("streamed %q, want %q", out.String(), tc.want)
+			}
+		})
+	}
+}
+
+func TestOpenedFilterHoldsReasoning(t *testing.T) {
+	var out strings.Builder
+	f := NewOpenedFilter(func(delta string) error {
+		out.WriteString(delta)
+		return nil
+	}, 100)
+
+	if err := f.Write("Two and two"); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("streamed %q before the reasoning ended", out.String())
+	}
+}
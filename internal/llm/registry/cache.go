@@ -0,0 +1,89 @@
+package registry
+
+import (
+	"context"
+	"time"
+
+	"github.com/shanto-323/axis/internal/llm/cache"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestKey identifies requests with the same answer, for the response
+// cache and to coalesce identical requests in flight. It is empty for
+// requests with tools since tool results change over time.
+func requestKey(request *dto.ChatRequest, history []dto.ChatMessage) string {
+	if len(request.Tools) > 0 {
+		return ""
+	}
+
+	return cache.Key(request.Model, requestMessages(request, history), request.GenerationParams, request.ResponseFormat)
+}
+
+// cacheable reports whether the answer to request may be reused. Only
+// requests sampled at temperature 0 or with a seed are expected to get the
+// same answer twice; anything else would always replay one sample.
+func (r *Registry) cacheable(key string, request *dto.ChatRequest) bool {
+	if r.cache == nil || key == "" || request.NoCache {
+		return false
+	}
+
+	params := request.GenerationParams
+	return (params.Temperature != nil && *params.Temperature == 0) || params.Seed != nil
+}
+
+// cachedResponse looks the key up. Cache failures are logged and treated
+// as misses.
+func (r *Registry) cachedResponse(ctx context.Context, key string, request *dto.ChatRequest, startTime time.Time) *dto.ConversationLogResponse {
+	if !r.cacheable(key, request) {
+		return nil
+	}
+
+	span := trace.SpanFromContext(ctx)
+
+	cached, err := r.cache.Get(ctx, key)
+	if err != nil {
+		r.logger.Warn().
+			Err(err).
+			Str("event", "llm-cache").
+			Msg("response cache lookup failed")
+		span.RecordError(err)
+		return nil
+	}
+	if cached == nil {
+		span.AddEvent("llm.cache.miss")
+		return nil
+	}
+
+	span.AddEvent("llm.cache.hit", trace.WithAttributes(
+		attribute.String("llm.model", cached.LLMModelName),
+	))
+
+	cached.Cached = true
+	cached.TextQuery = request.Message
+	cached.TimeTaken = int(time.Since(startTime).Seconds())
+	cached.Timestamp = time.Now()
+
+	r.logger.Info().
+		Str("event", "llm-response").
+		Str("model", cached.LLMModelName).
+		Bool("cached", true).
+		Msg("success")
+
+	return cached
+}
+
+func (r *Registry) cacheResponse(ctx context.Context, key string, request *dto.ChatRequest, response *dto.ConversationLogResponse) {
+	if !r.cacheable(key, request) {
+		return
+	}
+
+	if err := r.cache.Set(ctx, key, response); err != nil {
+		r.logger.Warn().
+			Err(err).
+			Str("event", "llm-cache").
+			Msg("failed to cache response")
+		trace.SpanFromContext(ctx).RecordError(err)
+	}
+}
@@ -0,0 +1,64 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shanto-323/axis/internal/llm"
+	"github.com/shanto-323/axis/internal/llm/tokens"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"github.com/shanto-323/axis/internal/model/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// cancelled reports whether the caller gave up on the generation, because
+// the client went away or asked to cancel it, rather than it timing out.
+func cancelled(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.Canceled)
+}
+
+// cancelledResponse is the answer to a cancelled generation, holding the
+// content the client was sent before. Providers report no usage for an
+// interrupted call, so it is estimated. A caller that left a call still
+// running for other requests caused no usage of its own and is charged
+// none, like any coalesced answer.
+func (r *Registry) cancelledResponse(ctx context.Context, request *dto.ChatRequest, history []dto.ChatMessage, entry llm.ModelEntry, content string, shared bool, startTime time.Time, event string) *dto.ConversationLogResponse {
+	// Without a model tried, e.g. when the call was shared with other
+	// requests, the requested one is assumed.
+	if entry.Name == "" {
+		if chain, err := r.chain(request.Model); err == nil {
+			entry = chain[0]
+		}
+	}
+
+	completion := &llm.Completion{Content: content}
+	if !shared {
+		estimator := tokens.ForModel(entry.Model)
+		completion.PromptTokens = estimator.Prompt(requestMessages(request, history))
+		completion.CompletionTokens = estimator.Count(content)
+		completion.TotalTokens = completion.PromptTokens + completion.CompletionTokens
+	}
+
+	response := newResponse(request, entry, completion, nil, startTime)
+	response.Status = entity.ConversationLogStatusCancelled
+	response.Coalesced = shared
+
+	cause := context.Cause(ctx)
+	trace.SpanFromContext(ctx).AddEvent("llm.cancelled", trace.WithAttributes(
+		attribute.String("llm.model", entry.Name),
+		attribute.String("llm.cancel.cause", cause.Error()),
+		attribute.Int("llm.partial_tokens", completion.CompletionTokens),
+	))
+
+	r.logger.Info().
+		Err(cause).
+		Str("event", event).
+		Str("model", entry.Name).
+		Int("time", response.TimeTaken).
+		Int("partial_tokens", completion.CompletionTokens).
+		Msg("cancelled")
+
+	return response
+}
@@ -0,0 +1,129 @@
+package registry
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/shanto-323/axis/config"
+	"github.com/shanto-323/axis/internal/llm"
+)
+
+//go:embed models.json
+var defaultCatalog []byte
+
+// loadCatalog reads the catalog file, or the built-in one, applies the
+// environment overrides and drops disabled models and models whose
+// provider is not configured.
+func (r *Registry) loadCatalog() (llm.LLMModels, error) {
+	raw := defaultCatalog
+	if path := r.config.AiManage.CatalogFile; path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read model catalog: %w", err)
+		}
+		raw = b
+	}
+
+	catalog := map[string]config.ModelConfig{}
+	if err := json.Unmarshal(raw, &catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse model catalog: %w", err)
+	}
+
+	for alias, m := range r.config.AiManage.Models {
+		catalog[alias] = m
+	}
+
+	models := llm.LLMModels{}
+	for alias, m := range catalog {
+		if m.Provider == "" || m.Model == "" {
+			return nil, fmt.Errorf("model %s: provider and model are required", alias)
+		}
+
+		if !m.IsEnabled() {
+			continue
+		}
+
+		if _, ok := r.providers[m.Provider]; !ok {
+			r.logger.Warn().
+				Str("model", alias).
+				Str("provider", m.Provider).
+				Msg("skipping model, provider not configured")
+			continue
+		}
+
+		displayName := m.DisplayName
+		if displayName == "" {
+			displayName = alias
+		}
+
+		modalities := m.Modalities
+		if len(modalities) == 0 {
+			modalities = []string{llm.ModalityText}
+		}
+
+		maxOutputTokens := m.MaxOutputTokens
+		if maxOutputTokens == 0 {
+			maxOutputTokens = m.ContextLength
+		}
+
+		models[alias] = llm.ModelEntry{
+			Name:              alias,
+			DisplayName:       displayName,
+			Provider:          m.Provider,
+			Model:             m.Model,
+			ContextLength:     m.ContextLength,
+			Modalities:        modalities,
+			Reasoning:         m.Reasoning,
+			ThinkOpened:       m.ThinkOpened,
+			StructuredOutputs: m.StructuredOutputs,
+			MaxOutputTokens:   maxOutputTokens,
+			InputPrice:        m.InputPrice,
+			OutputPrice:       m.OutputPrice,
+			Fallbacks:         m.Fallbacks,
+		}
+	}
+
+	// Only a configured default is checked; catalogs without the built-in
+	// one keep working for requests that name their model.
+	if alias := r.config.AiManage.DefaultModel; alias != "" {
+		if _, ok := models[alias]; !ok {
+			return nil, fmt.Errorf("default model %s is not in the catalog", alias)
+		}
+	}
+
+	return models, nil
+}
+
+// Reload re-reads the model catalog. The current catalog is kept when the
+// new one cannot be loaded.
+func (r *Registry) Reload() error {
+	models, err := r.loadCatalog()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.llmModels = models
+	r.mu.Unlock()
+
+	r.logger.Info().
+		Int("models", len(models)).
+		Msg("model catalog reloaded")
+
+	if r.refresh != nil {
+		select {
+		case r.refresh <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+func (r *Registry) models() llm.LLMModels {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.llmModels
+}
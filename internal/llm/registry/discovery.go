@@ -0,0 +1,117 @@
+package registry
+
+import (
+	"context"
+	"time"
+
+	"github.com/shanto-323/axis/internal/llm"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	defaultDiscoveryInterval = 10 * time.Minute
+	discoveryTimeout         = 30 * time.Second
+)
+
+// runDiscovery checks the catalog against the providers' model listings
+// until the registry is closed. A pass also runs right after a reload.
+func (r *Registry) runDiscovery(interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultDiscoveryInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.discover()
+
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		case <-r.refresh:
+		}
+	}
+}
+
+func (r *Registry) discover() {
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+
+	ctx, span := r.tracer.Start(ctx, "event.model_discovery")
+	defer span.End()
+
+	byProvider := map[string][]llm.ModelEntry{}
+	for _, m := range r.models() {
+		byProvider[m.Provider] = append(byProvider[m.Provider], m)
+	}
+
+	statuses := map[string]string{}
+	for name, entries := range byProvider {
+		lister, ok := r.providers[name].(llm.ModelLister)
+		if !ok {
+			continue
+		}
+
+		upstream, err := lister.ListModels(ctx)
+		if err != nil {
+			r.logger.Warn().
+				Err(err).
+				Str("event", "model-discovery").
+				Str("provider", name).
+				Msg("failed to list provider models")
+			span.RecordError(err)
+			continue
+		}
+
+		listed := make(map[string]llm.UpstreamModel, len(upstream))
+		for _, u := range upstream {
+			listed[u.ID] = u
+		}
+
+		for _, m := range entries {
+			u, ok := listed[m.Model]
+			switch {
+			case !ok:
+				statuses[m.Name] = llm.ModelStatusMissing
+				r.logger.Warn().
+					Str("event", "model-discovery").
+					Str("model", m.Name).
+					Str("provider", name).
+					Msg("model no longer listed by provider")
+			case u.Deprecated:
+				statuses[m.Name] = llm.ModelStatusDeprecated
+			default:
+				statuses[m.Name] = llm.ModelStatusAvailable
+			}
+		}
+	}
+
+	r.mu.Lock()
+	for alias, status := range statuses {
+		r.statuses[alias] = status
+	}
+	r.mu.Unlock()
+
+	span.SetAttributes(attribute.Int("discovery.checked", len(statuses)))
+}
+
+func (r *Registry) modelStatus(alias string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	status, ok := r.statuses[alias]
+	if !ok {
+		return llm.ModelStatusUnknown
+	}
+	return status
+}
+
+// Close stops background discovery.
+func (r *Registry) Close() error {
+	if r.stop != nil {
+		close(r.stop)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/llm/jsonschema"
+	"github.com/shanto-323/axis/internal/model/dto"
+)
+
+const defaultMaxRepairs = 2
+
+// outputFormat checks answers requested with a JSON response_format.
+type outputFormat struct {
+	format *dto.ResponseFormat
+	schema *jsonschema.Schema
+}
+
+func newOutputFormat(format *dto.ResponseFormat) (*outputFormat, error) {
+	if !format.IsJSON() {
+		return nil, nil
+	}
+
+	f := &outputFormat{format: format}
+	if format.Type == dto.ResponseFormatJSONSchema {
+		schema, err := jsonschema.Compile(format.JSONSchema.Schema)
+		if err != nil {
+			return nil, err
+		}
+		f.schema = schema
+	}
+
+	return f, nil
+}
+
+func (r *Registry) maxRepairs() int {
+	switch n := r.config.AiManage.StructuredOutput.MaxRepairs; {
+	case n == 0:
+		return defaultMaxRepairs
+	case n < 0:
+		return 0
+	default:
+		return n
+	}
+}
+
+// check parses and validates an answer. It returns the JSON without a
+// surrounding markdown code fence, which models without native support
+// tend to add.
+func (f *outputFormat) check(content string) (string, []errs.FieldError) {
+	content = stripCodeFence(content)
+
+	var v any
+	if err := json.Unmarshal([]byte(content), &v); err != nil {
+		return content, []errs.FieldError{{Field: "#", Error: "not valid JSON: " + err.Error()}}
+	}
+
+	if f.schema == nil {
+		if _, ok := v.(map[string]any); !ok {
+			return content, []errs.FieldError{{Field: "#", Error: "must be a JSON object"}}
+		}
+		return content, nil
+	}
+
+	var fieldErrors []errs.FieldError
+	for _, violation := range f.schema.Validate(v) {
+		fieldErrors = append(fieldErrors, errs.FieldError{
+			Field: "#" + violation.Path,
+			Error: violation.Message,
+		})
+	}
+
+	return content, fieldErrors
+}
+
+// instruction asks for JSON in the prompt, for models that do not accept
+// response_format.
+func (f *outputFormat) instruction() string {
+	if f.schema == nil {
+		return "Respond only with a single valid JSON object, without any other text or code fences."
+	}
+
+	schema, _ := json.Marshal(f.format.JSONSchema.Schema)
+	return "Respond only with valid JSON, without any other text or code fences, that matches this JSON Schema:\n" + string(schema)
+}
+
+// withInstruction adds the JSON instruction to the leading system message.
+func (f *outputFormat) withInstruction(messages []dto.ChatMessage) []dto.ChatMessage {
+	out := make([]dto.ChatMessage, 0, len(messages)+1)
+
+	if len(messages) > 0 && messages[0].Role == dto.RoleSystem {
+		first := messages[0]
+		first.Content += "\n\n" + f.instruction()
+		out = append(out, first)
+		return append(out, messages[1:]...)
+	}
+
+	out = append(out, dto.ChatMessage{Role: dto.RoleSystem, Content: f.instruction()})
+	return append(out, messages...)
+}
+
+func repairPrompt(fieldErrors []errs.FieldError) string {
+	var b strings.Builder
+	b.WriteString("Your previous reply does not match the required JSON format:\n")
+	for _, e := range fieldErrors {
+		fmt.Fprintf(&b, "- %s: %s\n", e.Field, e.Error)
+	}
+	b.WriteString("Reply again with only the corrected JSON.")
+	return b.String()
+}
+
+func stripCodeFence(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "```") || !strings.HasSuffix(content, "```") || len(content) < 6 {
+		return content
+	}
+
+	content = strings.TrimSuffix(strings.TrimPrefix(content, "```"), "```")
+	// Drop the language tag, e.g. ```json.
+	if i := strings.IndexByte(content, '\n'); i >= 0 && !strings.ContainsAny(content[:i], "{[") {
+		content = content[i+1:]
+	}
+
+	return strings.TrimSpace(content)
+}
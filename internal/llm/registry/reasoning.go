@@ -0,0 +1,62 @@
+package registry
+
+import (
+	"strings"
+
+	"github.com/shanto-323/axis/internal/llm"
+	"github.com/shanto-323/axis/internal/llm/reasoning"
+	"github.com/shanto-323/axis/internal/llm/tokens"
+)
+
+const defaultReasoningHoldBytes = 16 << 10
+
+// separateReasoning moves reasoning a reasoning model wrote between <think>
+// tags out of the content. Other models keep the tags, which they only
+// write as text. Reasoning tokens the provider did not report are
+// estimated.
+func separateReasoning(completion *llm.Completion, entry llm.ModelEntry) {
+	if !entry.Reasoning {
+		return
+	}
+
+	if answer, thought := reasoning.Split(completion.Content); thought != "" {
+		completion.Content = answer
+		completion.Reasoning = joinReasoning(completion.Reasoning, thought)
+	}
+
+	if completion.ReasoningTokens == 0 && completion.Reasoning != "" {
+		estimated := tokens.ForModel(entry.Model).Count(completion.Reasoning)
+		completion.ReasoningTokens = min(estimated, completion.CompletionTokens)
+	}
+}
+
+func joinReasoning(a, b string) string {
+	if a == "" || b == "" {
+		return a + b
+	}
+	return strings.TrimSpace(a) + "\n\n" + strings.TrimSpace(b)
+}
+
+// reasoningHoldBytes is how much output of a think_opened model is held
+// back waiting for the end of its reasoning.
+func (r *Registry) reasoningHoldBytes() int {
+	if n := r.config.AiManage.Reasoning.HoldBytes; n > 0 {
+		return n
+	}
+	return defaultReasoningHoldBytes
+}
+
+// streamWriter returns the stream callback for entry and the flush to call
+// once its stream ends. The <think> blocks of reasoning models are kept
+// from send; other models stream unchanged.
+func (r *Registry) streamWriter(entry llm.ModelEntry, send llm.StreamFunc) (llm.StreamFunc, func() error) {
+	if !entry.Reasoning {
+		return send, func() error { return nil }
+	}
+
+	filter := reasoning.NewFilter(send)
+	if entry.ThinkOpened {
+		filter = reasoning.NewOpenedFilter(send, r.reasoningHoldBytes())
+	}
+	return filter.Write, filter.Flush
+}
@@ -0,0 +1,528 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shanto-323/axis/config"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/llm"
+	"github.com/shanto-323/axis/internal/llm/cache"
+	"github.com/shanto-323/axis/internal/llm/fake"
+	"github.com/shanto-323/axis/internal/llm/openrouter"
+	"github.com/shanto-323/axis/internal/llm/resilience"
+	"github.com/shanto-323/axis/internal/llm/tools"
+	"github.com/shanto-323/axis/internal/model"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"github.com/shanto-323/axis/internal/model/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Registry implements llm.LLM by routing each catalog alias to the
+// provider adapter that serves it.
+type Registry struct {
+	config *config.Config
+	logger *zerolog.Logger
+	tracer trace.Tracer
+
+	providers map[string]llm.Provider
+
+	mu        sync.RWMutex
+	llmModels llm.LLMModels
+	statuses  map[string]string
+
+	retry    resilience.Retry
+	breakers *resilience.Breakers
+
+	tools   *tools.Registry
+	cache   cache.Cache
+	flights *resilience.SingleFlight[*dto.ConversationLogResponse]
+
+	stop    chan struct{}
+	refresh chan struct{}
+}
+
+// New builds the registry. responses may be nil to disable the response
+// cache.
+func New(cfg *config.Config, log *zerolog.Logger, tracer trace.Tracer, toolset *tools.Registry, responses cache.Cache) (*Registry, error) {
+	providers := map[string]llm.Provider{}
+	for name, p := range cfg.AiManage.ProviderConfigs() {
+		switch p.Type {
+		case config.ProviderTypeOpenAI:
+			provider, err := openrouter.NewOpenrouter(name, p, log)
+			if err != nil {
+				return nil, err
+			}
+			providers[name] = provider
+		case config.ProviderTypeFake:
+			provider, err := fake.New(name, p, log)
+			if err != nil {
+				return nil, err
+			}
+			providers[name] = provider
+		default:
+			return nil, fmt.Errorf("provider %s: unsupported type %s", name, p.Type)
+		}
+	}
+
+	r := &Registry{
+		config:    cfg,
+		logger:    log,
+		tracer:    tracer,
+		providers: providers,
+		statuses:  map[string]string{},
+		retry:     resilience.NewRetry(cfg.AiManage.Resilience),
+		breakers:  resilience.NewBreakers(cfg.AiManage.Resilience),
+		tools:     toolset,
+		cache:     responses,
+		flights:   resilience.NewSingleFlight[*dto.ConversationLogResponse](),
+	}
+
+	models, err := r.loadCatalog()
+	if err != nil {
+		return nil, err
+	}
+	r.llmModels = models
+
+	if cfg.AiManage.Discovery.Enabled {
+		r.stop = make(chan struct{})
+		r.refresh = make(chan struct{}, 1)
+		go r.runDiscovery(cfg.AiManage.Discovery.Interval)
+	}
+
+	return r, nil
+}
+
+func (r *Registry) AvailableModels(ctx context.Context) *[]dto.LLMModel {
+	_, span := r.tracer.Start(ctx, "event.models")
+	defer span.End()
+
+	models := []dto.LLMModel{}
+	for _, v := range r.models() {
+		models = append(models, r.llmModel(v))
+	}
+
+	sort.Slice(models, func(i, j int) bool {
+		return models[i].Name < models[j].Name
+	})
+
+	span.SetAttributes(
+		attribute.String("get all models", "success"),
+	)
+
+	return &models
+}
+
+func (r *Registry) GetModel(name string) (*dto.LLMModel, bool) {
+	entry, ok := r.models()[name]
+	if !ok {
+		return nil, false
+	}
+
+	m := r.llmModel(entry)
+	return &m, true
+}
+
+func (r *Registry) llmModel(v llm.ModelEntry) dto.LLMModel {
+	return dto.LLMModel{
+		Name:              v.Name,
+		DisplayName:       v.DisplayName,
+		Model:             v.Model,
+		Provider:          v.Provider,
+		ContextLength:     v.ContextLength,
+		Modalities:        v.Modalities,
+		Reasoning:         v.Reasoning,
+		StructuredOutputs: v.StructuredOutputs,
+		MaxOutputTokens:   v.MaxOutputTokens,
+		InputPrice:        v.InputPrice,
+		OutputPrice:       v.OutputPrice,
+		Fallbacks:         v.Fallbacks,
+		Status:            r.modelStatus(v.Name),
+	}
+}
+
+func (r *Registry) GenerateResponse(ctx context.Context, request *dto.ChatRequest, history []dto.ChatMessage) (*dto.ConversationLogResponse, error) {
+	ctx, span := r.tracer.Start(ctx, "event.llm_response")
+	defer span.End()
+
+	startTime := time.Now()
+
+	key := requestKey(request, history)
+	if cached := r.cachedResponse(ctx, key, request, startTime); cached != nil {
+		return cached, nil
+	}
+
+	generate := func(ctx context.Context) (*dto.ConversationLogResponse, error) {
+		completion, entry, calls, err := r.generate(ctx, request, history, func(provider llm.Provider, _ llm.ModelEntry, req *llm.Request) (*llm.Completion, bool, error) {
+			completion, err := provider.Complete(ctx, req)
+			return completion, true, err
+		})
+		if err != nil && completion != nil {
+			return r.failedResponse(request, entry, completion, calls, startTime, "llm-response", err), err
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		response := r.conversationLogResponse(request, entry, completion, calls, startTime, "llm-response")
+		r.cacheResponse(ctx, key, request, response)
+
+		return response, nil
+	}
+
+	var response *dto.ConversationLogResponse
+	var shared bool
+	var err error
+	if key == "" {
+		response, err = generate(ctx)
+	} else {
+		// Identical requests in flight share one upstream call.
+		response, shared, err = r.flights.Do(ctx, key, generate)
+	}
+	if err != nil && cancelled(ctx) {
+		return r.cancelledResponse(ctx, request, history, llm.ModelEntry{}, "", shared, startTime, "llm-response"), nil
+	}
+	if err != nil && shared {
+		// Only the caller that owns the call pays for a failed one.
+		return nil, err
+	}
+	if err != nil || !shared {
+		return response, err
+	}
+
+	span.AddEvent("llm.coalesced", trace.WithAttributes(
+		attribute.String("llm.model", response.LLMModelName),
+	))
+
+	coalesced := *response
+	coalesced.Coalesced = true
+	coalesced.TextQuery = request.Message
+	coalesced.TimeTaken = int(time.Since(startTime).Seconds())
+	coalesced.Timestamp = time.Now()
+
+	return &coalesced, nil
+}
+
+func (r *Registry) GenerateStreamResponse(ctx context.Context, request *dto.ChatRequest, history []dto.ChatMessage, onDelta llm.StreamFunc) (*dto.ConversationLogResponse, error) {
+	ctx, span := r.tracer.Start(ctx, "event.llm_stream_response")
+	defer span.End()
+
+	startTime := time.Now()
+
+	// sent is what the client has seen over all turns and attempts, kept
+	// for cancelled generations.
+	var sent strings.Builder
+
+	completion, entry, calls, err := r.generate(ctx, request, history, func(provider llm.Provider, entry llm.ModelEntry, req *llm.Request) (*llm.Completion, bool, error) {
+		write, flush := r.streamWriter(entry, func(delta string) error {
+			sent.WriteString(delta)
+			return onDelta(delta)
+		})
+		completion, err := provider.CompleteStream(ctx, req, write)
+		if err == nil {
+			err = flush()
+		}
+		// Once the client has seen part of an answer, even from an earlier
+		// turn, no other attempt or model may add to it.
+		return completion, sent.Len() == 0, err
+	})
+	if err != nil && cancelled(ctx) {
+		return r.cancelledResponse(ctx, request, history, entry, sent.String(), false, startTime, "llm-stream-response"), nil
+	}
+	if err != nil && completion != nil {
+		return r.failedResponse(request, entry, completion, calls, startTime, "llm-stream-response", err), err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.conversationLogResponse(request, entry, completion, calls, startTime, "llm-stream-response"), nil
+}
+
+// attemptFunc runs one provider call. canRetry reports whether a failed
+// call may still be repeated, on the same model or the next in the chain.
+type attemptFunc func(provider llm.Provider, entry llm.ModelEntry) (completion *llm.Completion, canRetry bool, err error)
+
+// complete runs attempt against the requested model and then its fallbacks,
+// moving on only while the upstream errors are retryable or the model's
+// circuit breaker is open. Only models with all of the given modalities
+// are tried.
+func (r *Registry) complete(ctx context.Context, alias string, modalities []string, attempt attemptFunc) (*llm.Completion, llm.ModelEntry, error) {
+	span := trace.SpanFromContext(ctx)
+
+	chain, err := r.chain(alias, modalities...)
+	if err != nil {
+		return nil, llm.ModelEntry{}, err
+	}
+
+	for i, entry := range chain {
+		span.SetAttributes(
+			attribute.String("llm.provider", entry.Provider),
+			attribute.String("llm.model", entry.Model),
+		)
+
+		completion, canRetry, err := r.call(ctx, entry, attempt)
+		if err == nil {
+			return completion, entry, nil
+		}
+
+		r.logger.Error().
+			Err(err).
+			Str("event", "llm-response").
+			Str("provider", entry.Provider).
+			Str("model", entry.Name).
+			Msg("response gen failed")
+		span.RecordError(err)
+
+		last := i == len(chain)-1
+		if last || !canRetry || ctx.Err() != nil || !(llm.IsRetryable(err) || errors.Is(err, llm.ErrCircuitOpen)) {
+			return nil, llm.ModelEntry{}, upstreamError(alias, err)
+		}
+
+		span.AddEvent("llm.fallback", trace.WithAttributes(
+			attribute.String("llm.fallback.from", entry.Name),
+			attribute.String("llm.fallback.to", chain[i+1].Name),
+		))
+	}
+
+	return nil, llm.ModelEntry{}, errs.NewInternalServerError()
+}
+
+// upstreamError is the error reported for a model chain that did not
+// answer. A request the provider rejected is passed on as a bad request,
+// since retrying it elsewhere would not help either; anything else means
+// the models are unavailable for now.
+func upstreamError(alias string, err error) error {
+	if errors.Is(err, llm.ErrCircuitOpen) {
+		code := "MODEL_UNAVAILABLE"
+		return errs.NewServiceUnavailableError("model is temporarily unavailable :"+alias, true, &code)
+	}
+
+	var providerErr *llm.ProviderError
+	if errors.As(err, &providerErr) && providerErr.Kind == llm.ErrorKindBadRequest {
+		switch providerErr.StatusCode {
+		case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+			message := "model " + alias + " rejected the request"
+			if providerErr.Message != "" {
+				message += ": " + providerErr.Message
+			}
+			code := "UPSTREAM_REJECTED"
+			return errs.NewBadRequestError(message, true, &code, nil, nil)
+		}
+	}
+
+	// Rejected credentials or an unknown upstream model are ours to fix,
+	// not the client's.
+	code := "UPSTREAM_UNAVAILABLE"
+	return errs.NewServiceUnavailableError("no model could answer for "+alias+", try again later", true, &code)
+}
+
+// call runs attempt against a single model behind its circuit breaker,
+// retrying retryable failures with backoff.
+func (r *Registry) call(ctx context.Context, entry llm.ModelEntry, attempt attemptFunc) (*llm.Completion, bool, error) {
+	span := trace.SpanFromContext(ctx)
+	breaker := r.breakers.Get(entry.Name)
+
+	var completion *llm.Completion
+	canRetry := true
+
+	err := r.retry.Do(ctx, func() (bool, error) {
+		allowed, state := breaker.Allow()
+		r.recordBreakerState(span, entry, state)
+		if !allowed {
+			return false, llm.ErrCircuitOpen
+		}
+
+		var err error
+		completion, canRetry, err = attempt(r.providers[entry.Provider], entry)
+
+		state = breaker.Record(err)
+		r.recordBreakerState(span, entry, state)
+
+		// No point waiting for a retry the breaker would reject.
+		return canRetry && state != resilience.StateOpen, err
+	}, func(attempt int, delay time.Duration, err error) {
+		r.logger.Warn().
+			Err(err).
+			Str("event", "llm-retry").
+			Str("model", entry.Name).
+			Int("attempt", attempt).
+			Dur("delay", delay).
+			Msg("retrying provider call")
+
+		span.AddEvent("llm.retry", trace.WithAttributes(
+			attribute.String("llm.model", entry.Name),
+			attribute.Int("llm.retry.attempt", attempt),
+			attribute.Int64("llm.retry.delay_ms", delay.Milliseconds()),
+		))
+	})
+
+	return completion, canRetry, err
+}
+
+func (r *Registry) recordBreakerState(span trace.Span, entry llm.ModelEntry, state string) {
+	if state == "" {
+		return
+	}
+
+	r.logger.Warn().
+		Str("event", "llm-circuit-breaker").
+		Str("model", entry.Name).
+		Str("state", state).
+		Msg("circuit breaker state changed")
+
+	span.AddEvent("llm.circuit_breaker", trace.WithAttributes(
+		attribute.String("llm.model", entry.Name),
+		attribute.String("llm.circuit_breaker.state", state),
+	))
+}
+
+// CircuitBreakers reports the breaker state of every model that has seen traffic.
+func (r *Registry) CircuitBreakers() []dto.CircuitBreakerStatus {
+	return r.breakers.Statuses()
+}
+
+// CoalescedCalls counts the requests answered by sharing another request's
+// upstream call.
+func (r *Registry) CoalescedCalls() int64 {
+	return r.flights.Shared()
+}
+
+// chain returns the requested model followed by its usable fallbacks.
+// Models that discovery reports as missing are skipped, as are fallbacks
+// lacking one of the required modalities.
+func (r *Registry) chain(alias string, modalities ...string) ([]llm.ModelEntry, error) {
+	models := r.models()
+
+	requested, ok := models[alias]
+	if !ok {
+		code := "INVALID_MODEL_NAME"
+		return nil, errs.NewNotFoundError("no such model found :"+alias, true, &code)
+	}
+
+	for _, modality := range modalities {
+		if !requested.HasModality(modality) {
+			code := "UNSUPPORTED_MODALITY"
+			return nil, errs.NewBadRequestError(fmt.Sprintf("model %s does not accept %s input", alias, modality), true, &code, nil, nil)
+		}
+	}
+
+	seen := map[string]bool{}
+	chain := []llm.ModelEntry{}
+	for _, name := range append([]string{alias}, requested.Fallbacks...) {
+		entry, ok := models[name]
+		if !ok || seen[name] || r.modelStatus(name) == llm.ModelStatusMissing {
+			continue
+		}
+		if _, ok := r.providers[entry.Provider]; !ok {
+			continue
+		}
+		if !hasModalities(entry, modalities) {
+			continue
+		}
+
+		seen[name] = true
+		chain = append(chain, entry)
+	}
+
+	if len(chain) == 0 {
+		code := "MODEL_UNAVAILABLE"
+		return nil, errs.NewServiceUnavailableError("model is no longer offered by its provider :"+alias, true, &code)
+	}
+
+	return chain, nil
+}
+
+func hasModalities(entry llm.ModelEntry, modalities []string) bool {
+	for _, modality := range modalities {
+		if !entry.HasModality(modality) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Registry) conversationLogResponse(request *dto.ChatRequest, entry llm.ModelEntry, completion *llm.Completion, calls []model.ToolCall, startTime time.Time, event string) *dto.ConversationLogResponse {
+	response := newResponse(request, entry, completion, calls, startTime)
+
+	r.logger.Info().
+		Str("event", event).
+		Str("model", entry.Name).
+		Int("time", response.TimeTaken).
+		Int("total_tokens", completion.TotalTokens).
+		Msg("success")
+
+	return response
+}
+
+// failedResponse records the usage of a generation that failed after
+// spending tokens.
+func (r *Registry) failedResponse(request *dto.ChatRequest, entry llm.ModelEntry, completion *llm.Completion, calls []model.ToolCall, startTime time.Time, event string, err error) *dto.ConversationLogResponse {
+	response := newResponse(request, entry, completion, calls, startTime)
+	response.Status = entity.ConversationLogStatusFailed
+
+	r.logger.Warn().
+		Err(err).
+		Str("event", event).
+		Str("model", entry.Name).
+		Int("time", response.TimeTaken).
+		Int("total_tokens", completion.TotalTokens).
+		Msg("failed")
+
+	return response
+}
+
+func newResponse(request *dto.ChatRequest, entry llm.ModelEntry, completion *llm.Completion, calls []model.ToolCall, startTime time.Time) *dto.ConversationLogResponse {
+	totalTime := int(time.Since(startTime).Seconds())
+
+	response := dto.ConversationLogResponse{
+		BaseGeneration: model.BaseGeneration{
+			PromptTokens:       completion.PromptTokens,
+			CompletionTokens:   completion.CompletionTokens,
+			TotalTokens:        completion.TotalTokens,
+			FinishReason:       completion.FinishReason,
+			ProviderResponseID: completion.ResponseID,
+			ReasoningTokens:    completion.ReasoningTokens,
+		},
+		TextQuery:    request.Message,
+		ResponseText: completion.Content,
+		Status:       entity.ConversationLogStatusCompleted,
+		TimeTaken:    totalTime,
+		Params:       fitParams(entry, request.GenerationParams),
+		ToolCalls:    calls,
+	}
+
+	response.LLMModelName = entry.Name
+	response.Timestamp = time.Now()
+
+	if entry.Name != request.Model {
+		response.FallbackFrom = &request.Model
+	}
+	if completion.Reasoning != "" {
+		response.Reasoning = &completion.Reasoning
+	}
+
+	return &response
+}
+
+// fitParams adapts request parameters to a model. They are validated against
+// the requested model, so this only matters for fallbacks with a smaller
+// output limit or without reasoning support.
+func fitParams(entry llm.ModelEntry, params model.GenerationParams) model.GenerationParams {
+	if params.MaxTokens != nil && entry.MaxOutputTokens > 0 && *params.MaxTokens > entry.MaxOutputTokens {
+		maxTokens := entry.MaxOutputTokens
+		params.MaxTokens = &maxTokens
+	}
+	if !entry.Reasoning {
+		params.ReasoningEffort = ""
+	}
+	return params
+}
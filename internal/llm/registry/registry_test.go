@@ -0,0 +1,321 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shanto-323/axis/config"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/llm"
+	"github.com/shanto-323/axis/internal/llm/cache"
+	"github.com/shanto-323/axis/internal/llm/resilience"
+	"github.com/shanto-323/axis/internal/llm/tools"
+	"github.com/shanto-323/axis/internal/model"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"github.com/shanto-323/axis/internal/model/entity"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func newRegistry(t *testing.T, provider config.ProviderConfig) *Registry {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.AiManage.Providers = map[string]config.ProviderConfig{"openrouter": provider}
+
+	log := zerolog.Nop()
+	r, err := New(cfg, &log, noop.NewTracerProvider().Tracer(""), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+
+	return r
+}
+
+// fakeProvider serves the built-in catalog from a fake provider running
+// script.
+func fakeProvider(t *testing.T, script string) config.ProviderConfig {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "script.json")
+	if err := os.WriteFile(path, []byte(script), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return config.ProviderConfig{Type: config.ProviderTypeFake, Script: path}
+}
+
+func TestDiscover(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object": "list", "data": [
+			{"id": "meta-llama/llama-3.3-70b-instruct:free", "object": "model", "created": 1, "owned_by": "meta-llama"},
+			{"id": "qwen/qwen3-coder:free", "object": "model", "created": 1, "owned_by": "qwen", "expiration_date": "2026-12-01"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	r := newRegistry(t, config.ProviderConfig{Type: config.ProviderTypeOpenAI, BaseURL: srv.URL})
+
+	if status := r.modelStatus("llama-70b"); status != llm.ModelStatusUnknown {
+		t.Errorf("before discovery: status = %s, want %s", status, llm.ModelStatusUnknown)
+	}
+
+	r.discover()
+
+	for alias, want := range map[string]string{
+		"llama-70b":    llm.ModelStatusAvailable,
+		"qwen3":        llm.ModelStatusDeprecated,
+		"nemotron-30b": llm.ModelStatusMissing,
+	} {
+		if _, ok := r.models()[alias]; !ok {
+			t.Fatalf("%s is not in the built-in catalog", alias)
+		}
+		if status := r.modelStatus(alias); status != want {
+			t.Errorf("%s: status = %s, want %s", alias, status, want)
+		}
+	}
+}
+
+func TestDiscoverKeepsStatusesWhenListingFails(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": {"message": "down"}}`, http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	r := newRegistry(t, config.ProviderConfig{Type: config.ProviderTypeOpenAI, BaseURL: srv.URL})
+	r.statuses["llama-70b"] = llm.ModelStatusAvailable
+
+	r.discover()
+
+	if status := r.modelStatus("llama-70b"); status != llm.ModelStatusAvailable {
+		t.Errorf("status = %s, want the last known %s", status, llm.ModelStatusAvailable)
+	}
+}
+
+func TestGenerateFallsBackOnUpstreamErrors(t *testing.T) {
+	r := newRegistry(t, fakeProvider(t, `{"rules": [
+		{"match": "hello", "model": "meta-llama/llama-3.3-70b-instruct:free", "error": "500"},
+		{"match": "hello", "response": "Hi from a fallback."}
+	]}`))
+
+	response, err := r.GenerateResponse(context.Background(), &dto.ChatRequest{Model: "llama-70b", Message: "hello"}, nil)
+	if err != nil {
+		t.Fatalf("GenerateResponse: %v", err)
+	}
+
+	if response.ResponseText != "Hi from a fallback." {
+		t.Errorf("ResponseText = %q", response.ResponseText)
+	}
+	if response.LLMModelName != "nemotron-30b" || response.FallbackFrom == nil || *response.FallbackFrom != "llama-70b" {
+		t.Errorf("answered by %s, fallback from %v; want nemotron-30b for llama-70b", response.LLMModelName, response.FallbackFrom)
+	}
+}
+
+func TestGenerateReportsUpstreamFailures(t *testing.T) {
+	for name, tc := range map[string]struct {
+		error  string
+		status int
+		code   string
+	}{
+		"rejected request":     {"400", http.StatusBadRequest, "UPSTREAM_REJECTED"},
+		"fallbacks exhausted":  {"500", http.StatusServiceUnavailable, "UPSTREAM_UNAVAILABLE"},
+		"rejected credentials": {"401", http.StatusServiceUnavailable, "UPSTREAM_UNAVAILABLE"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := newRegistry(t, fakeProvider(t, `{"rules": [{"error": "`+tc.error+`"}]}`))
+			r.retry = resilience.NewRetry(config.ResilienceConfig{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+			_, err := r.GenerateResponse(context.Background(), &dto.ChatRequest{Model: "llama-70b", Message: "hello"}, nil)
+
+			var httpErr *errs.HTTPError
+			if !errors.As(err, &httpErr) || httpErr.Status != tc.status || httpErr.Code != tc.code {
+				t.Errorf("err = %v, want %d %s", err, tc.status, tc.code)
+			}
+		})
+	}
+}
+
+func TestGenerateStreamKeepsReasoningOut(t *testing.T) {
+	r := newRegistry(t, fakeProvider(t, `{"chunk_size": 1, "rules": [
+		{"match": "sum", "response": "<think>Two and two.</think>\n\nIt is 4."}
+	]}`))
+
+	var streamed strings.Builder
+	response, err := r.GenerateStreamResponse(context.Background(), &dto.ChatRequest{Model: "nemotron-30b", Message: "sum"}, nil, func(delta string) error {
+		streamed.WriteString(delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("GenerateStreamResponse: %v", err)
+	}
+
+	if streamed.String() != "It is 4." || response.ResponseText != "It is 4." {
+		t.Errorf("streamed %q, answered %q; want %q", streamed.String(), response.ResponseText, "It is 4.")
+	}
+	if response.Reasoning == nil || *response.Reasoning != "Two and two." {
+		t.Errorf("Reasoning = %v, want %q", response.Reasoning, "Two and two.")
+	}
+}
+
+func TestGenerateStreamDoesNotFallBackAfterSending(t *testing.T) {
+	r := newRegistry(t, fakeProvider(t, `{"rules": [
+		{"match": "add", "response": "Let me check.", "tool_calls": [{"id": "call-1", "name": "calculator", "arguments": "{\"expression\": \"2+2\"}"}]},
+		{"match": "^4$", "model": "meta-llama/llama-3.3-70b-instruct:free", "error": "500"},
+		{"match": "^4$", "response": "Four."}
+	]}`))
+	r.tools = tools.New(config.ToolsConfig{}, nil)
+	r.retry = resilience.NewRetry(config.ResilienceConfig{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	var streamed strings.Builder
+	_, err := r.GenerateStreamResponse(context.Background(), &dto.ChatRequest{
+		Model:   "llama-70b",
+		Message: "add two and two",
+		Tools:   []string{"calculator"},
+	}, nil, func(delta string) error {
+		streamed.WriteString(delta)
+		return nil
+	})
+
+	if err == nil {
+		t.Fatal("the failed second turn was answered by a fallback")
+	}
+	if streamed.String() != "Let me check." {
+		t.Errorf("streamed %q, want only the first turn", streamed.String())
+	}
+}
+
+func TestGenerateKeepsThinkTagsOfOtherModels(t *testing.T) {
+	const answer = "Wrap it in <think>...</think> tags."
+	r := newRegistry(t, fakeProvider(t, `{"chunk_size": 1, "rules": [
+		{"match": "tags", "response": "Wrap it in <think>...</think> tags."}
+	]}`))
+	request := &dto.ChatRequest{Model: "llama-70b", Message: "which tags?"}
+
+	response, err := r.GenerateResponse(context.Background(), request, nil)
+	if err != nil {
+		t.Fatalf("GenerateResponse: %v", err)
+	}
+	if response.ResponseText != answer || response.Reasoning != nil {
+		t.Errorf("answered %q with reasoning %v; want %q without", response.ResponseText, response.Reasoning, answer)
+	}
+
+	var streamed strings.Builder
+	response, err = r.GenerateStreamResponse(context.Background(), request, nil, func(delta string) error {
+		streamed.WriteString(delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("GenerateStreamResponse: %v", err)
+	}
+	if streamed.String() != answer || response.ResponseText != answer {
+		t.Errorf("streamed %q, answered %q; want %q", streamed.String(), response.ResponseText, answer)
+	}
+}
+
+func TestGenerateReturnsUsageOfRejectedOutput(t *testing.T) {
+	r := newRegistry(t, fakeProvider(t, `{"rules": [
+		{"response": "not json at all"}
+	]}`))
+
+	response, err := r.GenerateResponse(context.Background(), &dto.ChatRequest{
+		Model:          "llama-70b",
+		Message:        "give me json",
+		ResponseFormat: &dto.ResponseFormat{Type: dto.ResponseFormatJSONObject},
+	}, nil)
+
+	var httpErr *errs.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Code != "INVALID_MODEL_OUTPUT" {
+		t.Fatalf("err = %v, want INVALID_MODEL_OUTPUT", err)
+	}
+	if response == nil || response.Status != entity.ConversationLogStatusFailed {
+		t.Fatalf("response = %+v, want a failed response", response)
+	}
+
+	// The first answer and every repair are paid for.
+	attempts := defaultMaxRepairs + 1
+	if response.CompletionTokens != attempts*4 {
+		t.Errorf("CompletionTokens = %d, want %d", response.CompletionTokens, attempts*4)
+	}
+}
+
+func TestGenerateCachesOnlyDeterministicRequests(t *testing.T) {
+	r := newRegistry(t, fakeProvider(t, `{"rules": [
+		{"response": "Hello."}
+	]}`))
+	r.cache = cache.NewMemory(time.Hour, 10)
+
+	zero, warm := 0.0, 0.7
+	seed := int64(7)
+	for name, tc := range map[string]struct {
+		params model.GenerationParams
+		cached bool
+	}{
+		"provider default": {model.GenerationParams{}, false},
+		"temperature 0.7":  {model.GenerationParams{Temperature: &warm}, false},
+		"temperature 0":    {model.GenerationParams{Temperature: &zero}, true},
+		"seed":             {model.GenerationParams{Temperature: &warm, Seed: &seed}, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			request := &dto.ChatRequest{Model: "llama-70b", Message: "hello " + name, GenerationParams: tc.params}
+			if _, err := r.GenerateResponse(context.Background(), request, nil); err != nil {
+				t.Fatalf("GenerateResponse: %v", err)
+			}
+
+			response, err := r.GenerateResponse(context.Background(), request, nil)
+			if err != nil {
+				t.Fatalf("GenerateResponse: %v", err)
+			}
+			if response.Cached != tc.cached {
+				t.Errorf("Cached = %t, want %t", response.Cached, tc.cached)
+			}
+		})
+	}
+}
+
+func TestGenerateChargesNothingToWaiterLeavingSharedCall(t *testing.T) {
+	r := newRegistry(t, fakeProvider(t, `{"latency": "200ms", "rules": [
+		{"response": "Hello."}
+	]}`))
+	request := func() *dto.ChatRequest { return &dto.ChatRequest{Model: "llama-70b", Message: "hello"} }
+
+	done := make(chan *dto.ConversationLogResponse, 1)
+	go func() {
+		response, err := r.GenerateResponse(context.Background(), request(), nil)
+		if err != nil {
+			t.Errorf("GenerateResponse: %v", err)
+		}
+		done <- response
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	// A second client asks the same and leaves while the call runs on.
+	ctx, leave := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, leave)
+
+	left, err := r.GenerateResponse(ctx, request(), nil)
+	if err != nil {
+		t.Fatalf("GenerateResponse: %v", err)
+	}
+	if left.Status != entity.ConversationLogStatusCancelled || !left.Coalesced {
+		t.Errorf("Status = %s, Coalesced = %t; want a cancelled coalesced response", left.Status, left.Coalesced)
+	}
+	if left.PromptTokens != 0 || left.CompletionTokens != 0 || left.TotalTokens != 0 {
+		t.Errorf("charged %d/%d/%d tokens, want none", left.PromptTokens, left.CompletionTokens, left.TotalTokens)
+	}
+
+	if owner := <-done; owner == nil || owner.ResponseText != "Hello." || owner.PromptTokens == 0 {
+		t.Errorf("owner = %+v, want the answer with its usage", owner)
+	}
+}
@@ -0,0 +1,197 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/llm"
+	"github.com/shanto-323/axis/internal/model"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const defaultMaxToolIterations = 5
+
+// sendFunc sends one model turn to a provider. canRetry has the meaning of
+// attemptFunc's.
+type sendFunc func(provider llm.Provider, entry llm.ModelEntry, request *llm.Request) (completion *llm.Completion, canRetry bool, err error)
+
+// generate answers a chat request, running the tools the model asks for and
+// sending their results back until the model answers without calling a
+// tool. The last allowed turn asks the model not to call tools. A JSON
+// answer that does not match the requested response_format is sent back
+// for repair a limited number of times. Usage is summed over all turns. On
+// failure entry is the model tried last, if any, and completion holds the
+// usage of the turns answered so far when output never matched the
+// response_format.
+func (r *Registry) generate(ctx context.Context, request *dto.ChatRequest, history []dto.ChatMessage, send sendFunc) (*llm.Completion, llm.ModelEntry, []model.ToolCall, error) {
+	tools, err := r.tools.Select(request.Tools)
+	if err != nil {
+		code := "INVALID_TOOL"
+		return nil, llm.ModelEntry{}, nil, errs.NewBadRequestError(err.Error(), true, &code, nil, nil)
+	}
+
+	format, err := newOutputFormat(request.ResponseFormat)
+	if err != nil {
+		return nil, llm.ModelEntry{}, nil, errs.NewBadRequestError("invalid response_format: "+err.Error(), true, nil, nil, nil)
+	}
+
+	maxIterations := r.config.AiManage.Tools.MaxIterations
+	if maxIterations == 0 {
+		maxIterations = defaultMaxToolIterations
+	}
+
+	messages := requestMessages(request, history)
+
+	var modalities []string
+	if len(request.Attachments) > 0 {
+		modalities = append(modalities, llm.ModalityImage)
+	}
+
+	// Later turns stay on the model that answered the first one.
+	alias := request.Model
+	total := &llm.Completion{}
+	var calls []model.ToolCall
+	repairs := 0
+	var attempted llm.ModelEntry
+
+	for iteration := 1; ; iteration++ {
+		last := iteration >= maxIterations
+
+		completion, entry, err := r.complete(ctx, alias, modalities, func(provider llm.Provider, entry llm.ModelEntry) (*llm.Completion, bool, error) {
+			attempted = entry
+			req := &llm.Request{
+				Model:        entry.Model,
+				Messages:     messages,
+				Params:       fitParams(entry, request.GenerationParams),
+				Tools:        tools,
+				DisableTools: last,
+			}
+			if format != nil {
+				if entry.StructuredOutputs {
+					req.ResponseFormat = request.ResponseFormat
+				} else {
+					req.Messages = format.withInstruction(messages)
+				}
+			}
+			return send(provider, entry, req)
+		})
+		if err != nil {
+			return nil, attempted, nil, err
+		}
+		alias = entry.Name
+		separateReasoning(completion, entry)
+
+		total.PromptTokens += completion.PromptTokens
+		total.CompletionTokens += completion.CompletionTokens
+		total.TotalTokens += completion.TotalTokens
+		total.ReasoningTokens += completion.ReasoningTokens
+		total.Reasoning = joinReasoning(total.Reasoning, completion.Reasoning)
+		total.Content = completion.Content
+		total.FinishReason = completion.FinishReason
+		total.ResponseID = completion.ResponseID
+
+		if len(tools) == 0 || len(completion.ToolCalls) == 0 || last {
+			if format == nil {
+				return total, entry, calls, nil
+			}
+
+			content, fieldErrors := format.check(completion.Content)
+			if len(fieldErrors) == 0 {
+				total.Content = content
+				return total, entry, calls, nil
+			}
+
+			if repairs >= r.maxRepairs() {
+				code := "INVALID_MODEL_OUTPUT"
+				return total, entry, calls, errs.NewUnprocessableEntityError("model output does not match response_format", true, &code, fieldErrors)
+			}
+			repairs++
+
+			trace.SpanFromContext(ctx).AddEvent("llm.repair", trace.WithAttributes(
+				attribute.String("llm.model", entry.Name),
+				attribute.Int("llm.repair.attempt", repairs),
+				attribute.Int("llm.repair.errors", len(fieldErrors)),
+			))
+
+			messages = append(messages,
+				dto.ChatMessage{Role: dto.RoleAssistant, Content: completion.Content},
+				dto.ChatMessage{Role: dto.RoleUser, Content: repairPrompt(fieldErrors)},
+			)
+			continue
+		}
+
+		messages = append(messages, dto.ChatMessage{
+			Role:      dto.RoleAssistant,
+			Content:   completion.Content,
+			ToolCalls: completion.ToolCalls,
+		})
+
+		for _, call := range completion.ToolCalls {
+			call = r.runTool(ctx, tools, call, iteration)
+			calls = append(calls, call)
+
+			result := call.Result
+			if call.Error != "" {
+				result = "error: " + call.Error
+			}
+			messages = append(messages, dto.ChatMessage{
+				Role:       dto.RoleTool,
+				Content:    result,
+				ToolCallID: call.ID,
+			})
+		}
+	}
+}
+
+// requestMessages appends the new user message, with its images, to the
+// history.
+func requestMessages(request *dto.ChatRequest, history []dto.ChatMessage) []dto.ChatMessage {
+	messages := make([]dto.ChatMessage, 0, len(history)+1)
+	messages = append(messages, history...)
+	return append(messages, dto.ChatMessage{Role: dto.RoleUser, Content: request.Message, Images: request.Attachments})
+}
+
+// runTool runs one tool call in its own span. Failures are recorded on the
+// call and reported back to the model rather than failing the request.
+func (r *Registry) runTool(ctx context.Context, tools []llm.Tool, call model.ToolCall, iteration int) model.ToolCall {
+	ctx, span := r.tracer.Start(ctx, "event.tool_call", trace.WithAttributes(
+		attribute.String("tool.name", call.Name),
+		attribute.String("tool.call_id", call.ID),
+		attribute.Int("tool.iteration", iteration),
+	))
+	defer span.End()
+
+	call.Iteration = iteration
+	start := time.Now()
+
+	result, err := callTool(ctx, tools, call)
+	call.DurationMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		r.logger.Warn().
+			Err(err).
+			Str("event", "tool-call").
+			Str("tool", call.Name).
+			Msg("tool call failed")
+		span.RecordError(err)
+
+		call.Error = err.Error()
+		return call
+	}
+
+	call.Result = result
+	return call
+}
+
+func callTool(ctx context.Context, tools []llm.Tool, call model.ToolCall) (string, error) {
+	for _, t := range tools {
+		if t.Name() == call.Name {
+			return t.Call(ctx, call.Arguments)
+		}
+	}
+	return "", fmt.Errorf("tool %s is not available", call.Name)
+}
@@ -0,0 +1,165 @@
+package resilience
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shanto-323/axis/config"
+	"github.com/shanto-323/axis/internal/llm"
+	"github.com/shanto-323/axis/internal/model/dto"
+)
+
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half_open"
+)
+
+// Breaker is a consecutive-failure circuit breaker. After Threshold
+// upstream failures in a row it opens and rejects calls for Cooldown, then
+// lets a single probe through; the probe's outcome closes or re-opens it.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// Allow reports whether a call may go upstream. It returns the state the
+// breaker moved to when the call turned it into a half-open probe.
+func (b *Breaker) Allow() (bool, string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false, ""
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+		return true, StateHalfOpen
+	case StateHalfOpen:
+		if b.probing {
+			return false, ""
+		}
+		b.probing = true
+		return true, ""
+	default:
+		return true, ""
+	}
+}
+
+// Record feeds the outcome of an allowed call back into the breaker and
+// returns the new state if it changed. Only errors that say something
+// about the upstream's health count as failures.
+func (b *Breaker) Record(err error) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	previous := b.state
+	b.probing = false
+
+	switch {
+	case err == nil:
+		b.failures = 0
+		b.state = StateClosed
+	case llm.IsRetryable(err):
+		b.failures++
+		if b.state == StateHalfOpen || b.failures >= b.threshold {
+			b.state = StateOpen
+			b.openedAt = time.Now()
+		}
+	case b.state == StateHalfOpen:
+		// The probe did not reach a verdict, e.g. a bad request.
+		// Let the next call probe again.
+	}
+
+	if b.state == previous {
+		return ""
+	}
+	return b.state
+}
+
+func (b *Breaker) status(model string) dto.CircuitBreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := dto.CircuitBreakerStatus{
+		Model:    model,
+		State:    b.state,
+		Failures: b.failures,
+	}
+	if b.state != StateClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	return status
+}
+
+// Breakers keeps one Breaker per catalog model.
+type Breakers struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+func NewBreakers(cfg config.ResilienceConfig) *Breakers {
+	b := &Breakers{
+		threshold: cfg.BreakerThreshold,
+		cooldown:  cfg.BreakerCooldown,
+		breakers:  map[string]*Breaker{},
+	}
+	if b.threshold == 0 {
+		b.threshold = defaultBreakerThreshold
+	}
+	if b.cooldown == 0 {
+		b.cooldown = defaultBreakerCooldown
+	}
+	return b
+}
+
+func (b *Breakers) Get(model string) *Breaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	breaker, ok := b.breakers[model]
+	if !ok {
+		breaker = &Breaker{
+			threshold: b.threshold,
+			cooldown:  b.cooldown,
+			state:     StateClosed,
+		}
+		b.breakers[model] = breaker
+	}
+	return breaker
+}
+
+// Statuses returns the state of every breaker that has seen traffic.
+func (b *Breakers) Statuses() []dto.CircuitBreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statuses := make([]dto.CircuitBreakerStatus, 0, len(b.breakers))
+	for model, breaker := range b.breakers {
+		statuses = append(statuses, breaker.status(model))
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Model < statuses[j].Model
+	})
+
+	return statuses
+}
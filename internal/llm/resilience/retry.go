@@ -0,0 +1,86 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/shanto-323/axis/config"
+	"github.com/shanto-323/axis/internal/llm"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBaseDelay   = 500 * time.Millisecond
+	defaultMaxDelay    = 10 * time.Second
+)
+
+// Retry runs provider calls again on retryable errors with jittered
+// exponential backoff. A Retry-After from the provider takes precedence
+// over the computed delay, capped at MaxDelay.
+type Retry struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+func NewRetry(cfg config.ResilienceConfig) Retry {
+	r := Retry{
+		MaxAttempts: cfg.MaxAttempts,
+		BaseDelay:   cfg.BaseDelay,
+		MaxDelay:    cfg.MaxDelay,
+	}
+	if r.MaxAttempts == 0 {
+		r.MaxAttempts = defaultMaxAttempts
+	}
+	if r.BaseDelay == 0 {
+		r.BaseDelay = defaultBaseDelay
+	}
+	if r.MaxDelay == 0 {
+		r.MaxDelay = defaultMaxDelay
+	}
+	return r
+}
+
+// Do calls fn until it succeeds, returns a non-retryable error, reports
+// that it cannot be retried, or MaxAttempts is reached. onRetry is called
+// before every wait.
+func (r Retry) Do(ctx context.Context, fn func() (canRetry bool, err error), onRetry func(attempt int, delay time.Duration, err error)) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		var canRetry bool
+		canRetry, err = fn()
+		if err == nil || !canRetry || !llm.IsRetryable(err) || attempt >= r.MaxAttempts {
+			return err
+		}
+
+		delay := r.delay(attempt, err)
+		if onRetry != nil {
+			onRetry(attempt, delay, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+func (r Retry) delay(attempt int, err error) time.Duration {
+	var providerErr *llm.ProviderError
+	if errors.As(err, &providerErr) && providerErr.RetryAfter > 0 {
+		return min(providerErr.RetryAfter, r.MaxDelay)
+	}
+
+	backoff := r.BaseDelay << (attempt - 1)
+	if backoff <= 0 || backoff > r.MaxDelay {
+		backoff = r.MaxDelay
+	}
+
+	// Full jitter keeps concurrent callers from retrying in lockstep.
+	return time.Duration(rand.Int64N(int64(backoff)) + 1)
+}
@@ -0,0 +1,98 @@
+package resilience
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+type flight[T any] struct {
+	done    chan struct{}
+	result  T
+	err     error
+	waiters int
+	claimed bool
+	cancel  context.CancelFunc
+}
+
+// SingleFlight runs one call per key at a time. Callers that arrive while a
+// call for their key is running wait for it and share its result.
+//
+// The call runs on a context detached from the callers' contexts. It is
+// cancelled once every waiting caller has given up, so one client leaving
+// does not fail the others.
+type SingleFlight[T any] struct {
+	mu      sync.Mutex
+	flights map[string]*flight[T]
+	shared  atomic.Int64
+}
+
+func NewSingleFlight[T any]() *SingleFlight[T] {
+	return &SingleFlight[T]{flights: map[string]*flight[T]{}}
+}
+
+// Do returns fn's result for key and whether it was shared. The first
+// caller to receive a result owns it, every later one shares it, so a
+// result is owned even when the caller that started the call gave up. A
+// caller that gives up gets its context's error, shared when the call goes
+// on for the callers still waiting.
+func (g *SingleFlight[T]) Do(ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (T, bool, error) {
+	g.mu.Lock()
+	f, ok := g.flights[key]
+	if ok {
+		f.waiters++
+	} else {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight[T]{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		g.flights[key] = f
+
+		go func() {
+			defer cancel()
+
+			f.result, f.err = fn(callCtx)
+
+			g.mu.Lock()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+			g.mu.Unlock()
+
+			close(f.done)
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		g.mu.Lock()
+		shared := f.claimed
+		f.claimed = true
+		g.mu.Unlock()
+
+		if shared && f.err == nil {
+			g.shared.Add(1)
+		}
+		return f.result, shared, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		shared := f.waiters > 0
+		if !shared {
+			f.cancel()
+			// Later callers must not join a cancelled call.
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+		}
+		g.mu.Unlock()
+
+		var zero T
+		return zero, shared, ctx.Err()
+	}
+}
+
+// Shared counts the calls answered with another caller's result, i.e. the
+// upstream calls saved.
+func (g *SingleFlight[T]) Shared() int64 {
+	return g.shared.Load()
+}
@@ -0,0 +1,92 @@
+// Package tokens estimates prompt sizes without running the models'
+// tokenizers. Estimates err on the high side so estimated prompts fit.
+package tokens
+
+import (
+	"math"
+	"strings"
+
+	"github.com/shanto-323/axis/internal/model/dto"
+)
+
+const (
+	// messageOverhead covers the role and separators of a chat message,
+	// replyOverhead the tokens that start the model's answer.
+	messageOverhead = 4
+	replyOverhead   = 3
+
+	// imageTokens is a flat estimate per attached image.
+	imageTokens = 1000
+
+	defaultCharsPerToken = 3.2
+)
+
+type family struct {
+	name          string
+	names         []string
+	charsPerToken float64
+}
+
+// families are matched against the upstream model id, e.g.
+// "meta-llama/llama-3.3-70b-instruct:free". The ratios approximate the
+// characters per token of each family's tokenizer on English text and code.
+var families = []family{
+	{"gpt", []string{"gpt"}, 4.0},
+	{"llama", []string{"llama"}, 3.8},
+	{"qwen", []string{"qwen"}, 3.7},
+	{"deepseek", []string{"deepseek"}, 3.6},
+	{"mistral", []string{"mistral", "devstral", "codestral"}, 3.4},
+}
+
+// Estimator counts tokens for one model family.
+type Estimator struct {
+	Family        string
+	charsPerToken float64
+}
+
+// ForModel returns the estimator of the family of an upstream model id.
+// Unknown models get a conservative default.
+func ForModel(model string) Estimator {
+	id := strings.ToLower(model)
+	for _, f := range families {
+		for _, name := range f.names {
+			if strings.Contains(id, name) {
+				return Estimator{Family: f.name, charsPerToken: f.charsPerToken}
+			}
+		}
+	}
+	return Estimator{Family: "default", charsPerToken: defaultCharsPerToken}
+}
+
+// Count estimates the tokens of text. Characters outside the Latin
+// alphabets, CJK for instance, are counted as a token each.
+func (e Estimator) Count(text string) int {
+	latin, other := 0, 0
+	for _, r := range text {
+		if r < 0x0300 {
+			latin++
+		} else {
+			other++
+		}
+	}
+	return int(math.Ceil(float64(latin)/e.charsPerToken)) + other
+}
+
+// Message estimates the tokens of one chat message, including its tool
+// calls and images.
+func (e Estimator) Message(m dto.ChatMessage) int {
+	n := messageOverhead + e.Count(m.Content)
+	for _, call := range m.ToolCalls {
+		n += e.Count(call.Name) + e.Count(call.Arguments)
+	}
+	return n + len(m.Images)*imageTokens
+}
+
+// Prompt estimates the tokens of a whole prompt.
+func (e Estimator) Prompt(messages []dto.ChatMessage) int {
+	n := replyOverhead
+	for _, m := range messages {
+		n += e.Message(m)
+	}
+	return n
+}
@@ -0,0 +1,221 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"unicode"
+)
+
+// Calculator evaluates arithmetic expressions with + - * / % ^ and
+// parentheses.
+type Calculator struct{}
+
+func (Calculator) Name() string { return "calculator" }
+
+func (Calculator) Description() string {
+	return "Evaluates an arithmetic expression. Supports + - * / % ^ and parentheses."
+}
+
+func (Calculator) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"expression": map[string]any{
+				"type":        "string",
+				"description": "Expression to evaluate, for example (2 + 3) * 4 ^ 2.",
+			},
+		},
+		"required":             []string{"expression"},
+		"additionalProperties": false,
+	}
+}
+
+func (Calculator) Call(ctx context.Context, arguments string) (string, error) {
+	var args struct {
+		Expression string `json:"expression"`
+	}
+	if err := decodeArguments(arguments, &args); err != nil {
+		return "", err
+	}
+
+	value, err := evaluate(args.Expression)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64), nil
+}
+
+// evaluate parses expression with a recursive descent parser:
+//
+//	expr   = term { ("+" | "-") term }
+//	term   = unary { ("*" | "/" | "%") unary }
+//	unary  = ("-" | "+") unary | power
+//	power  = factor [ "^" unary ]
+//	factor = number | "(" expr ")"
+//
+// so -2^2 is -4 and ^ is right associative.
+func evaluate(expression string) (float64, error) {
+	p := &parser{input: []rune(expression)}
+
+	value, err := p.expr()
+	if err != nil {
+		return 0, err
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return 0, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+	}
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return 0, fmt.Errorf("result is not a finite number")
+	}
+
+	return value, nil
+}
+
+type parser struct {
+	input []rune
+	pos   int
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+// next returns the next non-space rune without consuming it.
+func (p *parser) next() (rune, bool) {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return 0, false
+	}
+	return p.input[p.pos], true
+}
+
+func (p *parser) expr() (float64, error) {
+	left, err := p.term()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		op, ok := p.next()
+		if !ok || (op != '+' && op != '-') {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.term()
+		if err != nil {
+			return 0, err
+		}
+		if op == '+' {
+			left += right
+		} else {
+			left -= right
+		}
+	}
+}
+
+func (p *parser) term() (float64, error) {
+	left, err := p.unary()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		op, ok := p.next()
+		if !ok || (op != '*' && op != '/' && op != '%') {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.unary()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case '*':
+			left *= right
+		case '/':
+			if right == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			left /= right
+		case '%':
+			if right == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			left = math.Mod(left, right)
+		}
+	}
+}
+
+func (p *parser) power() (float64, error) {
+	base, err := p.factor()
+	if err != nil {
+		return 0, err
+	}
+
+	if op, ok := p.next(); ok && op == '^' {
+		p.pos++
+		exponent, err := p.unary()
+		if err != nil {
+			return 0, err
+		}
+		return math.Pow(base, exponent), nil
+	}
+
+	return base, nil
+}
+
+func (p *parser) unary() (float64, error) {
+	op, ok := p.next()
+	if ok && (op == '-' || op == '+') {
+		p.pos++
+		value, err := p.unary()
+		if op == '-' {
+			value = -value
+		}
+		return value, err
+	}
+	return p.power()
+}
+
+func (p *parser) factor() (float64, error) {
+	c, ok := p.next()
+	if !ok {
+		return 0, fmt.Errorf("unexpected end of expression")
+	}
+
+	if c == '(' {
+		p.pos++
+		value, err := p.expr()
+		if err != nil {
+			return 0, err
+		}
+		if c, ok := p.next(); !ok || c != ')' {
+			return 0, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return value, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && (unicode.IsDigit(p.input[p.pos]) || p.input[p.pos] == '.') {
+		p.pos++
+	}
+	if start == p.pos {
+		return 0, fmt.Errorf("unexpected %q at position %d", c, start)
+	}
+
+	value, err := strconv.ParseFloat(string(p.input[start:p.pos]), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", string(p.input[start:p.pos]))
+	}
+	return value, nil
+}
@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/internal/model/entity"
+)
+
+// HistoryStore is the part of the database the chat history tool reads.
+type HistoryStore interface {
+	SearchConversationLogs(ctx context.Context, userId uuid.UUID, query string, limit int) ([]entity.ConversationLog, error)
+}
+
+// ChatHistory searches the requesting user's earlier questions and answers.
+type ChatHistory struct {
+	store HistoryStore
+}
+
+func NewChatHistory(store HistoryStore) *ChatHistory {
+	return &ChatHistory{store: store}
+}
+
+func (*ChatHistory) Name() string { return "chat_history" }
+
+func (*ChatHistory) Description() string {
+	return "Searches the user's earlier chat messages and answers for a phrase, newest first."
+}
+
+func (*ChatHistory) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"query": map[string]any{
+				"type":        "string",
+				"description": "Phrase to look for, case insensitive.",
+			},
+			"limit": map[string]any{
+				"type":        "integer",
+				"minimum":     1,
+				"maximum":     20,
+				"description": "Maximum number of results. Defaults to 5.",
+			},
+		},
+		"required":             []string{"query"},
+		"additionalProperties": false,
+	}
+}
+
+func (t *ChatHistory) Call(ctx context.Context, arguments string) (string, error) {
+	var args struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+	if err := decodeArguments(arguments, &args); err != nil {
+		return "", err
+	}
+	if args.Query == "" {
+		return "", fmt.Errorf("query is required")
+	}
+	if args.Limit <= 0 {
+		args.Limit = 5
+	}
+	args.Limit = min(args.Limit, 20)
+
+	userId, ok := userIDFromContext(ctx)
+	if !ok {
+		return "", fmt.Errorf("no user in context")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	logs, err := t.store.SearchConversationLogs(ctx, userId, args.Query, args.Limit)
+	if err != nil {
+		return "", err
+	}
+
+	type match struct {
+		Timestamp time.Time `json:"timestamp"`
+		Model     string    `json:"model"`
+		Query     string    `json:"query"`
+		Response  string    `json:"response"`
+	}
+
+	matches := make([]match, 0, len(logs))
+	for _, l := range logs {
+		matches = append(matches, match{
+			Timestamp: l.Timestamp,
+			Model:     l.LLMModelName,
+			Query:     truncate(l.TextQuery, 500),
+			Response:  truncate(l.ResponseText, 1000),
+		})
+	}
+
+	return encodeResult(matches)
+}
+
+func truncate(s string, maxRunes int) string {
+	r := []rune(s)
+	if len(r) <= maxRunes {
+		return s
+	}
+	return string(r[:maxRunes]) + "..."
+}
@@ -0,0 +1,166 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shanto-323/axis/config"
+)
+
+const maxHTTPResponseBytes = 16 << 10
+
+// HTTPRequest calls internal HTTP services. Only URLs under one of the
+// configured prefixes may be requested, redirects included.
+type HTTPRequest struct {
+	allowed []*url.URL
+	client  *http.Client
+}
+
+func NewHTTPRequest(cfg config.HTTPToolConfig) *HTTPRequest {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	t := &HTTPRequest{}
+	for _, a := range cfg.AllowedURLs {
+		// Validated with the config.
+		u, _ := url.Parse(a)
+		t.allowed = append(t.allowed, u)
+	}
+
+	t.client = &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return errors.New("too many redirects")
+			}
+			if !t.isAllowed(req.URL) {
+				return fmt.Errorf("redirect to %s is not allowed", req.URL)
+			}
+			return nil
+		},
+	}
+
+	return t
+}
+
+func (*HTTPRequest) Name() string { return "http_request" }
+
+func (t *HTTPRequest) Description() string {
+	prefixes := make([]string, 0, len(t.allowed))
+	for _, u := range t.allowed {
+		prefixes = append(prefixes, u.String())
+	}
+	return "Sends a GET or POST request to an internal service. Allowed URL prefixes: " + strings.Join(prefixes, ", ")
+}
+
+func (*HTTPRequest) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"method": map[string]any{
+				"type": "string",
+				"enum": []string{http.MethodGet, http.MethodPost},
+			},
+			"url": map[string]any{
+				"type": "string",
+			},
+			"body": map[string]any{
+				"type":        "string",
+				"description": "JSON request body for POST.",
+			},
+		},
+		"required":             []string{"method", "url"},
+		"additionalProperties": false,
+	}
+}
+
+func (t *HTTPRequest) Call(ctx context.Context, arguments string) (string, error) {
+	var args struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+		Body   string `json:"body"`
+	}
+	if err := decodeArguments(arguments, &args); err != nil {
+		return "", err
+	}
+
+	if args.Method != http.MethodGet && args.Method != http.MethodPost {
+		return "", fmt.Errorf("method must be GET or POST")
+	}
+
+	u, err := url.Parse(args.URL)
+	if err != nil || !t.isAllowed(u) {
+		return "", fmt.Errorf("url %s is not allowed", args.URL)
+	}
+
+	var body io.Reader
+	if args.Method == http.MethodPost {
+		body = strings.NewReader(args.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, args.Method, u.String(), body)
+	if err != nil {
+		return "", err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPResponseBytes+1))
+	if err != nil {
+		return "", err
+	}
+
+	truncated := len(b) > maxHTTPResponseBytes
+	if truncated {
+		b = b[:maxHTTPResponseBytes]
+	}
+
+	return encodeResult(map[string]any{
+		"status":    resp.StatusCode,
+		"body":      string(b),
+		"truncated": truncated,
+	})
+}
+
+// isAllowed reports whether u is under one of the allowed prefixes, comparing
+// scheme and host exactly and the path by segments.
+func (t *HTTPRequest) isAllowed(u *url.URL) bool {
+	if u.User != nil {
+		return false
+	}
+
+	// Dot segments would let a request escape its prefix on the server.
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment == "." || segment == ".." {
+			return false
+		}
+	}
+
+	for _, a := range t.allowed {
+		if !strings.EqualFold(u.Scheme, a.Scheme) || !strings.EqualFold(u.Host, a.Host) {
+			continue
+		}
+
+		prefix := strings.TrimSuffix(a.Path, "/")
+		if u.Path == prefix || strings.HasPrefix(u.Path, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
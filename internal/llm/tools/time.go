@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CurrentTime reports the current time, in UTC or a given IANA time zone.
+type CurrentTime struct{}
+
+func (CurrentTime) Name() string { return "current_time" }
+
+func (CurrentTime) Description() string {
+	return "Returns the current date and time."
+}
+
+func (CurrentTime) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"timezone": map[string]any{
+				"type":        "string",
+				"description": "IANA time zone such as Europe/Berlin. Defaults to UTC.",
+			},
+		},
+		"additionalProperties": false,
+	}
+}
+
+func (CurrentTime) Call(ctx context.Context, arguments string) (string, error) {
+	var args struct {
+		Timezone string `json:"timezone"`
+	}
+	if err := decodeArguments(arguments, &args); err != nil {
+		return "", err
+	}
+
+	loc := time.UTC
+	if args.Timezone != "" {
+		l, err := time.LoadLocation(args.Timezone)
+		if err != nil {
+			return "", fmt.Errorf("unknown time zone %s", args.Timezone)
+		}
+		loc = l
+	}
+
+	now := time.Now().In(loc)
+	return encodeResult(map[string]string{
+		"time":     now.Format(time.RFC3339),
+		"weekday":  now.Weekday().String(),
+		"timezone": loc.String(),
+	})
+}
@@ -0,0 +1,102 @@
+// Package tools holds the server-side tools models can call during a chat.
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/config"
+	"github.com/shanto-323/axis/internal/llm"
+)
+
+// Registry is the set of tools chat requests may choose from.
+type Registry struct {
+	tools map[string]llm.Tool
+}
+
+func NewRegistry(tools ...llm.Tool) *Registry {
+	r := &Registry{tools: make(map[string]llm.Tool, len(tools))}
+	for _, t := range tools {
+		r.tools[t.Name()] = t
+	}
+	return r
+}
+
+// New registers the built-in tools. The chat history tool needs store, the
+// http_request tool an allowlist.
+func New(cfg config.ToolsConfig, store HistoryStore) *Registry {
+	tools := []llm.Tool{
+		CurrentTime{},
+		Calculator{},
+	}
+	if store != nil {
+		tools = append(tools, NewChatHistory(store))
+	}
+	if len(cfg.HTTP.AllowedURLs) > 0 {
+		tools = append(tools, NewHTTPRequest(cfg.HTTP))
+	}
+	return NewRegistry(tools...)
+}
+
+func (r *Registry) Get(name string) (llm.Tool, bool) {
+	t, ok := r.tools[name]
+	return t, ok
+}
+
+// Names lists the registered tools in alphabetical order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.tools))
+	for name := range r.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Select resolves tool names from a chat request.
+func (r *Registry) Select(names []string) ([]llm.Tool, error) {
+	selected := make([]llm.Tool, 0, len(names))
+	for _, name := range names {
+		t, ok := r.tools[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown tool %s", name)
+		}
+		selected = append(selected, t)
+	}
+	return selected, nil
+}
+
+type userIDKey struct{}
+
+// WithUserID makes the requesting user known to tools that read user data.
+func WithUserID(ctx context.Context, userId uuid.UUID) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userId)
+}
+
+func userIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	userId, ok := ctx.Value(userIDKey{}).(uuid.UUID)
+	return userId, ok
+}
+
+// decodeArguments unmarshals the model's arguments, treating an empty
+// string as an empty object.
+func decodeArguments(arguments string, v any) error {
+	if arguments == "" {
+		arguments = "{}"
+	}
+	if err := json.Unmarshal([]byte(arguments), v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+func encodeResult(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -14,3 +14,15 @@ type BaseLV struct {
 	BaseLLMModel
 	BaseTimestamp
 }
+
+// BaseGeneration holds what the provider reported about a completion.
+type BaseGeneration struct {
+	PromptTokens       int    `json:"prompt_tokens" db:"prompt_tokens"`
+	CompletionTokens   int    `json:"completion_tokens" db:"completion_tokens"`
+	TotalTokens        int    `json:"total_tokens" db:"total_tokens"`
+	FinishReason       string `json:"finish_reason" db:"finish_reason"`
+	ProviderResponseID string `json:"provider_response_id" db:"provider_response_id"`
+
+	// ReasoningTokens are the part of CompletionTokens spent on reasoning.
+	ReasoningTokens int `json:"reasoning_tokens" db:"reasoning_tokens"`
+}
@@ -0,0 +1,18 @@
+package model
+
+// ContextTruncation reports the conversation turns left out of a prompt so
+// it fits the model's context window, or sent as a summary. Token counts
+// are estimates.
+type ContextTruncation struct {
+	DroppedTurns  int `json:"dropped_turns"`
+	DroppedTokens int `json:"dropped_tokens"`
+	KeptTurns     int `json:"kept_turns"`
+	PromptTokens  int `json:"prompt_tokens"`
+	// Budget is the context length less the room left for the answer.
+	Budget int `json:"budget"`
+
+	// SummarizedTurns are the oldest turns replaced by version
+	// SummaryVersion of the conversation summary.
+	SummarizedTurns int `json:"summarized_turns"`
+	SummaryVersion  int `json:"summary_version,omitempty"`
+}
@@ -1,12 +1,69 @@
 package dto
 
 import (
+	"mime/multipart"
+
 	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/internal/model"
+	"github.com/shanto-323/axis/internal/validation"
+)
+
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
 )
 
+// ChatRequest is sent as JSON or, to upload images as files, as
+// multipart/form-data. Only the fields with a form tag are read from a
+// multipart form.
 type ChatRequest struct {
-	Model   string `json:"model"`
-	Message string `json:"message" validate:"required"`
+	ConversationID *uuid.UUID `json:"conversation_id" form:"conversation_id"`
+	Model          string     `json:"model" form:"model"`
+	Message        string     `json:"message" form:"message" validate:"required"`
+
+	// PersonaID and SystemPrompt are mutually exclusive ways to set the
+	// system message.
+	PersonaID    *uuid.UUID `json:"persona_id" form:"persona_id"`
+	SystemPrompt string     `json:"system_prompt" form:"system_prompt" validate:"max=20000"`
+
+	model.GenerationParams
+
+	// Tools names the server-side tools the model may call.
+	Tools []string `json:"tools" validate:"omitempty,max=10,dive,required,max=64"`
+
+	ResponseFormat *ResponseFormat `json:"response_format"`
+
+	// HideReasoning leaves the model's reasoning out of the answer. It is
+	// stored either way.
+	HideReasoning bool `json:"hide_reasoning" form:"hide_reasoning"`
+
+	// Images are base64 attachments of a JSON request, Files the image
+	// uploads of a multipart one.
+	Images []ImageInput            `json:"images" validate:"omitempty,dive"`
+	Files  []*multipart.FileHeader `json:"-" form:"images"`
+
+	// Attachments are the checked images, set by the chat service.
+	Attachments []ChatImage `json:"-"`
+
+	// NoCache skips the response cache, set from the Cache-Control header.
+	NoCache bool `json:"-"`
+}
+
+// ChatMessage is a single prior turn sent to the model ahead of the new message.
+type ChatMessage struct {
+	Role    string
+	Content string
+
+	// ToolCalls are the calls requested by an assistant turn, ToolCallID the
+	// call a tool turn answers.
+	ToolCalls  []model.ToolCall
+	ToolCallID string
+
+	// Images are sent as image content parts of a user turn.
+	Images []ChatImage
 }
 
 func (r *ChatRequest) Validate() error {
@@ -14,9 +71,34 @@ func (r *ChatRequest) Validate() error {
 		return err
 	}
 
-	if r.Model == "" {
-		r.Model = "llama_70b"
+	if r.PersonaID != nil && r.SystemPrompt != "" {
+		return validation.NewFieldError("system_prompt", "cannot be combined with persona_id")
+	}
+
+	if r.ResponseFormat != nil {
+		if err := r.ResponseFormat.Validate(); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
+
+type ChatStreamDelta struct {
+	Content string `json:"content"`
+}
+
+// CancelRequest names a chat request in progress by the X-Request-ID it
+// was sent or answered with.
+type CancelRequest struct {
+	RequestID string `param:"request_id" validate:"required,max=128"`
+}
+
+func (r *CancelRequest) Validate() error {
+	return validator.New().Struct(r)
+}
+
+type CancelResponse struct {
+	RequestID string `json:"request_id"`
+	Cancelled bool   `json:"cancelled"`
+}
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/internal/model"
+)
+
+// CompareRequest sends one message to several models side by side.
+type CompareRequest struct {
+	Message      string   `json:"message" validate:"required"`
+	Models       []string `json:"models" validate:"required,min=2,max=5,unique,dive,required"`
+	SystemPrompt string   `json:"system_prompt" validate:"max=20000"`
+
+	model.GenerationParams
+}
+
+func (r *CompareRequest) Validate() error {
+	return validator.New().Struct(r)
+}
+
+type CompareResponse struct {
+	ComparisonID uuid.UUID       `json:"comparison_id"`
+	Query        string          `json:"query"`
+	Results      []CompareResult `json:"results"`
+}
+
+// CompareResult is one model's answer, or its error. LogID is the stored
+// conversation log of a successful answer.
+type CompareResult struct {
+	Model        string     `json:"model"`
+	LogID        *uuid.UUID `json:"log_id"`
+	ResponseText string     `json:"response_text"`
+	Reasoning    *string    `json:"reasoning"`
+	LatencyMs    int64      `json:"latency_ms"`
+	FallbackFrom *string    `json:"fallback_from"`
+
+	model.BaseGeneration
+	Cost float64 `json:"cost"`
+
+	Error *string `json:"error"`
+}
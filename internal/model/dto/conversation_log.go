@@ -28,8 +28,20 @@ func (l *ConversationHistoryQuery) Validate() error {
 
 type ConversationLogResponse struct {
 	model.BaseLV
+	model.BaseGeneration
 
-	TextQuery    string   `json:"query"`
-	ResponseText string   `json:"response_text"`
-	TimeTaken    int      `json:"time_taken"`
+	TextQuery    string  `json:"query"`
+	ResponseText string  `json:"response_text"`
+	Reasoning    *string `json:"reasoning"`
+	Status       string  `json:"status"`
+	TimeTaken    int     `json:"time_taken"`
+	FallbackFrom *string `json:"fallback_from"`
+	// Cached is set when the answer came from the response cache.
+	Cached bool `json:"cached"`
+	// Coalesced is set when the answer was shared from an identical
+	// request in flight.
+	Coalesced bool `json:"coalesced"`
+
+	Params    model.GenerationParams `json:"params"`
+	ToolCalls []model.ToolCall       `json:"tool_calls"`
 }
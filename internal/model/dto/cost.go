@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/internal/model/entity"
+	"github.com/shanto-323/axis/internal/validation"
+)
+
+// CostReportQuery selects logs in [From, To). Dates are either YYYY-MM-DD or
+// RFC3339; a bare To date includes that whole day. The range defaults to the
+// current UTC month.
+type CostReportQuery struct {
+	From   string     `query:"from"`
+	To     string     `query:"to"`
+	UserID *uuid.UUID `query:"user_id"`
+	Model  string     `query:"model" validate:"omitempty,max=100"`
+
+	FromTime time.Time `query:"-"`
+	ToTime   time.Time `query:"-"`
+}
+
+func (q *CostReportQuery) Validate() error {
+	if err := validator.New().Struct(q); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	q.FromTime = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	q.ToTime = q.FromTime.AddDate(0, 1, 0)
+
+	if q.From != "" {
+		t, _, err := parseReportDate(q.From)
+		if err != nil {
+			return validation.NewFieldError("from", "must be YYYY-MM-DD or RFC3339")
+		}
+		q.FromTime = t
+	}
+
+	if q.To != "" {
+		t, dateOnly, err := parseReportDate(q.To)
+		if err != nil {
+			return validation.NewFieldError("to", "must be YYYY-MM-DD or RFC3339")
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		q.ToTime = t
+	}
+
+	if !q.ToTime.After(q.FromTime) {
+		return validation.NewFieldError("to", "must be after from")
+	}
+
+	return nil
+}
+
+func parseReportDate(s string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	return t, false, err
+}
+
+type CostReportResponse struct {
+	From      time.Time              `json:"from"`
+	To        time.Time              `json:"to"`
+	TotalCost float64                `json:"total_cost"`
+	Rows      []entity.CostReportRow `json:"rows"`
+}
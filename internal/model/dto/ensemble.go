@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/internal/model"
+)
+
+const (
+	// EnsembleModePick answers with the candidate the judge picks,
+	// EnsembleModeSynthesize with an answer the judge writes from all of them.
+	EnsembleModePick       = "pick"
+	EnsembleModeSynthesize = "synthesize"
+)
+
+// EnsembleRequest asks several models the same question and has a judge
+// model decide on the answer.
+type EnsembleRequest struct {
+	Message string   `json:"message" validate:"required"`
+	Models  []string `json:"models" validate:"required,min=2,max=5,unique,dive,required"`
+	// Judge defaults to AI_MANAGER.ENSEMBLE.JUDGE.
+	Judge        string `json:"judge"`
+	Mode         string `json:"mode" validate:"omitempty,oneof=pick synthesize"`
+	SystemPrompt string `json:"system_prompt" validate:"max=20000"`
+
+	model.GenerationParams
+}
+
+func (r *EnsembleRequest) Validate() error {
+	if err := validator.New().Struct(r); err != nil {
+		return err
+	}
+
+	if r.Mode == "" {
+		r.Mode = EnsembleModeSynthesize
+	}
+
+	return nil
+}
+
+// EnsembleResponse is the final answer with the candidates it was chosen or
+// synthesized from. ChosenModel is the candidate the judge rated best. Judge
+// is null when only one candidate answered and there was nothing to judge.
+type EnsembleResponse struct {
+	EnsembleID  uuid.UUID       `json:"ensemble_id"`
+	Query       string          `json:"query"`
+	Mode        string          `json:"mode"`
+	Answer      string          `json:"answer"`
+	ChosenModel *string         `json:"chosen_model"`
+	Rationale   string          `json:"rationale"`
+	Candidates  []CompareResult `json:"candidates"`
+	Judge       *CompareResult  `json:"judge"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+type CircuitBreakerStatus struct {
+	Model    string     `json:"model"`
+	State    string     `json:"state"`
+	Failures int        `json:"failures"`
+	OpenedAt *time.Time `json:"opened_at,omitempty"`
+}
+
+type HealthResponse struct {
+	Status          string                 `json:"status"`
+	CircuitBreakers []CircuitBreakerStatus `json:"circuit_breakers,omitempty"`
+	// CoalescedCalls counts the upstream calls saved by sharing the answer
+	// of an identical request in flight since the server started.
+	CoalescedCalls *int64 `json:"coalesced_calls,omitempty"`
+}
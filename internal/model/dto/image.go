@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"encoding/base64"
+
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+// ImageInput is an image attached to a JSON chat request. Data is either
+// plain base64 or a data URL; ContentType is informational, the type is
+// detected from the decoded bytes.
+type ImageInput struct {
+	Data        string `json:"data" validate:"required"`
+	ContentType string `json:"content_type"`
+}
+
+// ChatImage is a decoded and checked image sent to the model with the new
+// message.
+type ChatImage struct {
+	ContentType string
+	Data        []byte
+}
+
+// DataURL encodes the image the way OpenAI-compatible APIs accept inline
+// images.
+func (i ChatImage) DataURL() string {
+	return "data:" + i.ContentType + ";base64," + base64.StdEncoding.EncodeToString(i.Data)
+}
+
+type ImageIDRequest struct {
+	ID uuid.UUID `param:"id" validate:"required"`
+}
+
+func (r *ImageIDRequest) Validate() error {
+	return validator.New().Struct(r)
+}
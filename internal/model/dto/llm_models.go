@@ -1,6 +1,17 @@
 package dto
 
 type LLMModel struct {
-	Name  string `json:"name"`
-	Model string `json:"model"`
+	Name              string   `json:"name"`
+	DisplayName       string   `json:"display_name"`
+	Model             string   `json:"model"`
+	Provider          string   `json:"provider"`
+	ContextLength     int      `json:"context_length"`
+	Modalities        []string `json:"modalities"`
+	Reasoning         bool     `json:"reasoning"`
+	StructuredOutputs bool     `json:"structured_outputs"`
+	MaxOutputTokens   int      `json:"max_output_tokens"`
+	InputPrice        float64  `json:"input_price"`
+	OutputPrice       float64  `json:"output_price"`
+	Fallbacks         []string `json:"fallbacks"`
+	Status            string   `json:"status"`
 }
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/internal/model"
+)
+
+type PersonaRequest struct {
+	Name         string                 `json:"name" validate:"required,max=100"`
+	Description  string                 `json:"description" validate:"max=500"`
+	SystemPrompt string                 `json:"system_prompt" validate:"required,max=20000"`
+	DefaultModel string                 `json:"default_model" validate:"max=100"`
+	Params       model.GenerationParams `json:"params"`
+	Shared       bool                   `json:"shared"`
+}
+
+func (r *PersonaRequest) Validate() error {
+	return validator.New().Struct(r)
+}
+
+type UpdatePersonaRequest struct {
+	ID uuid.UUID `param:"id" json:"-"`
+	PersonaRequest
+}
+
+func (r *UpdatePersonaRequest) Validate() error {
+	return validator.New().Struct(r)
+}
+
+type PersonaIDRequest struct {
+	ID uuid.UUID `param:"id" validate:"required"`
+}
+
+func (r *PersonaIDRequest) Validate() error {
+	return validator.New().Struct(r)
+}
+
+type PersonaListRequest struct{}
+
+func (r *PersonaListRequest) Validate() error {
+	return nil
+}
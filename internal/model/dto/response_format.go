@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"github.com/go-playground/validator"
+	"github.com/shanto-323/axis/internal/llm/jsonschema"
+	"github.com/shanto-323/axis/internal/validation"
+)
+
+const (
+	ResponseFormatText       = "text"
+	ResponseFormatJSONObject = "json_object"
+	ResponseFormatJSONSchema = "json_schema"
+)
+
+// ResponseFormat follows the OpenAI request shape:
+//
+//	{"type": "json_schema", "json_schema": {"name": "...", "schema": {...}}}
+type ResponseFormat struct {
+	Type       string            `json:"type" validate:"required,oneof=text json_object json_schema"`
+	JSONSchema *JSONSchemaFormat `json:"json_schema"`
+}
+
+type JSONSchemaFormat struct {
+	Name        string         `json:"name" validate:"omitempty,max=64"`
+	Description string         `json:"description,omitempty"`
+	Schema      map[string]any `json:"schema"`
+	Strict      bool           `json:"strict"`
+}
+
+func (f *ResponseFormat) Validate() error {
+	if err := validator.New().Struct(f); err != nil {
+		return err
+	}
+
+	if f.Type != ResponseFormatJSONSchema {
+		return nil
+	}
+
+	if f.JSONSchema == nil || f.JSONSchema.Schema == nil {
+		return validation.NewFieldError("response_format", "json_schema.schema is required for type json_schema")
+	}
+	if _, err := jsonschema.Compile(f.JSONSchema.Schema); err != nil {
+		return validation.NewFieldError("response_format", "invalid schema: "+err.Error())
+	}
+	if f.JSONSchema.Name == "" {
+		f.JSONSchema.Name = "response"
+	}
+
+	return nil
+}
+
+// IsJSON reports whether the answer has to be JSON.
+func (f *ResponseFormat) IsJSON() bool {
+	return f != nil && f.Type != ResponseFormatText
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type UsageRequest struct{}
+
+func (r *UsageRequest) Validate() error {
+	return nil
+}
+
+// UsageAllowance is a single limit. Limit and Remaining are null when unlimited.
+type UsageAllowance struct {
+	Used      int  `json:"used"`
+	Limit     *int `json:"limit"`
+	Remaining *int `json:"remaining"`
+}
+
+type UsagePeriod struct {
+	ResetsAt time.Time      `json:"resets_at"`
+	Requests UsageAllowance `json:"requests"`
+	Tokens   UsageAllowance `json:"tokens"`
+	// ReasoningTokens is the part of the used tokens spent on reasoning.
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
+
+type UsageResponse struct {
+	Plan  string      `json:"plan"`
+	Day   UsagePeriod `json:"day"`
+	Month UsagePeriod `json:"month"`
+}
@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/internal/model"
+)
+
+type Conversation struct {
+	model.Base
+	model.BaseLLMModel
+
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Title  string    `db:"title" json:"title"`
+}
@@ -5,11 +5,43 @@ import (
 	"github.com/shanto-323/axis/internal/model"
 )
 
+// EnsembleRole values of logs stored by /chat/ensemble.
+const (
+	EnsembleRoleCandidate = "candidate"
+	EnsembleRoleJudge     = "judge"
+)
+
+// Status values of a log. A cancelled log keeps the part of the answer
+// generated before the cancellation, a failed one the tokens spent on an
+// answer that was rejected.
+const (
+	ConversationLogStatusCompleted = "completed"
+	ConversationLogStatusCancelled = "cancelled"
+	ConversationLogStatusFailed    = "failed"
+)
+
 type ConversationLog struct {
 	model.BaseId
 	model.BaseLV
+	model.BaseGeneration
+
+	UserID         uuid.UUID  `db:"user_id" json:"user_id"`
+	ConversationID *uuid.UUID `db:"conversation_id" json:"conversation_id"`
+	TextQuery      string     `db:"text_query" json:"query"`
+	ResponseText   string     `db:"response_text" json:"response_text"`
+	Reasoning      *string    `db:"reasoning" json:"reasoning"`
+	Status         string     `db:"status" json:"status"`
+	FallbackFrom   *string    `db:"fallback_from" json:"fallback_from"`
+	Cost           float64    `db:"cost" json:"cost"`
+	PersonaID      *uuid.UUID `db:"persona_id" json:"persona_id"`
+	Cached         bool       `db:"cached" json:"cached"`
+	Coalesced      bool       `db:"coalesced" json:"coalesced"`
+	ComparisonID   *uuid.UUID `db:"comparison_id" json:"comparison_id"`
+	EnsembleID     *uuid.UUID `db:"ensemble_id" json:"ensemble_id"`
+	EnsembleRole   *string    `db:"ensemble_role" json:"ensemble_role"`
 
-	UserID       uuid.UUID `db:"user_id" json:"user_id"`
-	TextQuery    string    `db:"text_query" json:"query"`
-	ResponseText string    `db:"response_text" json:"response_text"`
+	Params     model.GenerationParams   `db:"params" json:"params"`
+	ToolCalls  []model.ToolCall         `db:"tool_calls" json:"tool_calls"`
+	ImageIDs   []uuid.UUID              `db:"image_ids" json:"image_ids"`
+	Truncation *model.ContextTruncation `db:"truncation" json:"truncation"`
 }
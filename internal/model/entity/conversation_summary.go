@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/internal/model"
+)
+
+// ConversationSummary summarizes the first CoveredTurns turns of a
+// conversation. Model and PromptVersion identify the summarizer that wrote
+// it, so summaries can be regenerated when it changes.
+type ConversationSummary struct {
+	model.BaseId
+	model.BaseCreatedAt
+
+	ConversationID   uuid.UUID `db:"conversation_id" json:"conversation_id"`
+	Version          int       `db:"version" json:"version"`
+	Model            string    `db:"model" json:"model"`
+	PromptVersion    int       `db:"prompt_version" json:"prompt_version"`
+	CoveredTurns     int       `db:"covered_turns" json:"covered_turns"`
+	Summary          string    `db:"summary" json:"summary"`
+	PromptTokens     int       `db:"prompt_tokens" json:"prompt_tokens"`
+	CompletionTokens int       `db:"completion_tokens" json:"completion_tokens"`
+	Cost             float64   `db:"cost" json:"cost"`
+}
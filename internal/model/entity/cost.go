@@ -0,0 +1,14 @@
+package entity
+
+import "github.com/google/uuid"
+
+// CostReportRow aggregates the logs of one user and model.
+type CostReportRow struct {
+	UserID           uuid.UUID `db:"user_id" json:"user_id"`
+	LLMModelName     string    `db:"llm_model_name" json:"llm_model_name"`
+	Requests         int       `db:"requests" json:"requests"`
+	PromptTokens     int       `db:"prompt_tokens" json:"prompt_tokens"`
+	CompletionTokens int       `db:"completion_tokens" json:"completion_tokens"`
+	ReasoningTokens  int       `db:"reasoning_tokens" json:"reasoning_tokens"`
+	Cost             float64   `db:"cost" json:"cost"`
+}
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/internal/model"
+)
+
+// Image is an image a user attached to a chat request.
+type Image struct {
+	model.BaseId
+	model.BaseCreatedAt
+
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	ContentType string    `db:"content_type" json:"content_type"`
+	Size        int       `db:"size" json:"size"`
+	SHA256      string    `db:"sha256" json:"sha256"`
+	Data        []byte    `db:"data" json:"-"`
+}
@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"github.com/shanto-323/axis/internal/model"
+)
+
+// Persona is a reusable system prompt with default model and parameters.
+// Shared personas can be used, but not changed, by every user.
+type Persona struct {
+	model.Base
+
+	UserID       uuid.UUID              `db:"user_id" json:"user_id"`
+	Name         string                 `db:"name" json:"name"`
+	Description  string                 `db:"description" json:"description"`
+	SystemPrompt string                 `db:"system_prompt" json:"system_prompt"`
+	DefaultModel string                 `db:"default_model" json:"default_model"`
+	Params       model.GenerationParams `db:"params" json:"params"`
+	Shared       bool                   `db:"shared" json:"shared"`
+}
@@ -0,0 +1,25 @@
+package entity
+
+import "github.com/shanto-323/axis/internal/model"
+
+// QuotaPlan limits how much a user may use the chat API. A nil limit is unlimited.
+type QuotaPlan struct {
+	model.Base
+
+	Name             string `db:"name" json:"name"`
+	IsDefault        bool   `db:"is_default" json:"is_default"`
+	RequestsPerDay   *int   `db:"requests_per_day" json:"requests_per_day"`
+	TokensPerDay     *int   `db:"tokens_per_day" json:"tokens_per_day"`
+	RequestsPerMonth *int   `db:"requests_per_month" json:"requests_per_month"`
+	TokensPerMonth   *int   `db:"tokens_per_month" json:"tokens_per_month"`
+}
+
+// UsageTotals is a user's consumption in the current day and month.
+type UsageTotals struct {
+	RequestsToday            int
+	TokensToday              int
+	ReasoningTokensToday     int
+	RequestsThisMonth        int
+	TokensThisMonth          int
+	ReasoningTokensThisMonth int
+}
@@ -0,0 +1,43 @@
+package model
+
+// GenerationParams are optional sampling settings. Nil fields are left to
+// the provider's defaults.
+type GenerationParams struct {
+	Temperature      *float64 `json:"temperature,omitempty" validate:"omitempty,min=0,max=2"`
+	TopP             *float64 `json:"top_p,omitempty" validate:"omitempty,min=0,max=1"`
+	MaxTokens        *int     `json:"max_tokens,omitempty" validate:"omitempty,min=1"`
+	Stop             []string `json:"stop,omitempty" validate:"omitempty,max=4,dive,min=1,max=100"`
+	Seed             *int64   `json:"seed,omitempty"`
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty" validate:"omitempty,min=-2,max=2"`
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty" validate:"omitempty,min=-2,max=2"`
+	ReasoningEffort  string   `json:"reasoning_effort,omitempty" validate:"omitempty,oneof=minimal low medium high"`
+}
+
+// WithDefaults returns p with every unset field taken from defaults.
+func (p GenerationParams) WithDefaults(defaults GenerationParams) GenerationParams {
+	if p.Temperature == nil {
+		p.Temperature = defaults.Temperature
+	}
+	if p.TopP == nil {
+		p.TopP = defaults.TopP
+	}
+	if p.MaxTokens == nil {
+		p.MaxTokens = defaults.MaxTokens
+	}
+	if p.Stop == nil {
+		p.Stop = defaults.Stop
+	}
+	if p.Seed == nil {
+		p.Seed = defaults.Seed
+	}
+	if p.PresencePenalty == nil {
+		p.PresencePenalty = defaults.PresencePenalty
+	}
+	if p.FrequencyPenalty == nil {
+		p.FrequencyPenalty = defaults.FrequencyPenalty
+	}
+	if p.ReasoningEffort == "" {
+		p.ReasoningEffort = defaults.ReasoningEffort
+	}
+	return p
+}
@@ -0,0 +1,14 @@
+package model
+
+// ToolCall is a tool invocation requested by a model. Result, Error and
+// DurationMs are filled in once the tool has run.
+type ToolCall struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Arguments  string `json:"arguments"`
+	Result     string `json:"result,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	// Iteration is the model turn, starting at 1, that requested the call.
+	Iteration int `json:"iteration,omitempty"`
+}
@@ -24,6 +24,16 @@ type HandlerFunc[Req validation.Validatable, Res any] func(c echo.Context, req R
 
 type HandleNoResponseFunc[Req validation.Validatable] func(c echo.Context, req Req) error
 
+type HandleStreamFunc[Req validation.Validatable, Res any] func(c echo.Context, req Req, stream *SSEStream) (Res, error)
+
+type HandleFileFunc[Req validation.Validatable] func(c echo.Context, req Req) (*File, error)
+
+// File is a raw response body.
+type File struct {
+	ContentType string
+	Data        []byte
+}
+
 type ResponseHandler interface {
 	Handle(c echo.Context, result any) error
 	GetOperation() string
@@ -53,6 +63,31 @@ func (h NoResponseHandler) GetOperation() string {
 	return "handler_no_response"
 }
 
+type FileResponseHandler struct {
+	status int
+}
+
+func (h FileResponseHandler) Handle(c echo.Context, result any) error {
+	f := result.(*File)
+	return c.Blob(h.status, f.ContentType, f.Data)
+}
+
+func (h FileResponseHandler) GetOperation() string {
+	return "handler_file"
+}
+
+type StreamResponseHandler struct {
+	stream *SSEStream
+}
+
+func (h StreamResponseHandler) Handle(c echo.Context, result any) error {
+	return h.stream.Send(SSEEventDone, result)
+}
+
+func (h StreamResponseHandler) GetOperation() string {
+	return "handler_stream"
+}
+
 func handleRequest[Req validation.Validatable](
 	h *Handler,
 	c echo.Context,
@@ -171,3 +206,38 @@ func HandleNoResponse[Req validation.Validatable](
 		}, NoResponseHandler{status: status})
 	}
 }
+
+func HandleFile[Req validation.Validatable](
+	h *Handler,
+	handler HandleFileFunc[Req],
+	status int,
+	req Req,
+) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return handleRequest(h, c, req, func(c echo.Context, req Req) (any, error) {
+			return handler(c, req)
+		}, FileResponseHandler{status: status})
+	}
+}
+
+func HandleStream[Req validation.Validatable, Res any](
+	h *Handler,
+	handler HandleStreamFunc[Req, Res],
+	req Req,
+) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		stream := NewSSEStream(c)
+
+		err := handleRequest(h, c, req, func(c echo.Context, req Req) (any, error) {
+			return handler(c, req, stream)
+		}, StreamResponseHandler{stream: stream})
+
+		// Once the stream is open the error handler can no longer write JSON,
+		// so report the failure as a final event instead.
+		if err != nil && stream.Started() {
+			_ = stream.Error(err)
+		}
+
+		return err
+	}
+}
@@ -43,6 +43,59 @@ func (h *ChatHandler) ChatHandler() echo.HandlerFunc {
 	}
 }
 
+func (h *ChatHandler) ChatStreamHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return HandleStream(
+			h.Handler,
+			func(c echo.Context, req *dto.ChatRequest, stream *SSEStream) (*entity.ConversationLog, error) {
+				return h.service.ChatStream(c, req, func(delta string) error {
+					return stream.Send(SSEEventDelta, dto.ChatStreamDelta{Content: delta})
+				})
+			},
+			&dto.ChatRequest{},
+		)(c)
+	}
+}
+
+func (h *ChatHandler) CancelHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return Handle(
+			h.Handler,
+			func(c echo.Context, req *dto.CancelRequest) (*dto.CancelResponse, error) {
+				return h.service.Cancel(c, req)
+			},
+			http.StatusAccepted,
+			&dto.CancelRequest{},
+		)(c)
+	}
+}
+
+func (h *ChatHandler) CompareHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return Handle(
+			h.Handler,
+			func(c echo.Context, req *dto.CompareRequest) (*dto.CompareResponse, error) {
+				return h.service.Compare(c, req)
+			},
+			http.StatusOK,
+			&dto.CompareRequest{},
+		)(c)
+	}
+}
+
+func (h *ChatHandler) EnsembleHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return Handle(
+			h.Handler,
+			func(c echo.Context, req *dto.EnsembleRequest) (*dto.EnsembleResponse, error) {
+				return h.service.Ensemble(c, req)
+			},
+			http.StatusOK,
+			&dto.EnsembleRequest{},
+		)(c)
+	}
+}
+
 func (h *ChatHandler) ChatHistoryHandler() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		return Handle(
@@ -55,3 +108,20 @@ func (h *ChatHandler) ChatHistoryHandler() echo.HandlerFunc {
 		)(c)
 	}
 }
+
+func (h *ChatHandler) ImageHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return HandleFile(
+			h.Handler,
+			func(c echo.Context, req *dto.ImageIDRequest) (*File, error) {
+				img, err := h.service.Image(c, req)
+				if err != nil {
+					return nil, err
+				}
+				return &File{ContentType: img.ContentType, Data: img.Data}, nil
+			},
+			http.StatusOK,
+			&dto.ImageIDRequest{},
+		)(c)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/shanto-323/axis/internal/llm"
+	"github.com/shanto-323/axis/internal/model/dto"
 	"github.com/shanto-323/axis/internal/server"
 	"github.com/shanto-323/axis/internal/server/middleware"
 )
@@ -64,14 +66,14 @@ func (h *HealthHandler) CheckHealth(c echo.Context) error {
 			Str("error_type", "overall_unhealthy").
 			Int64("total_duration_ms", time.Since(start).Milliseconds()).
 			Msg("HealthCheckError")
-		return c.JSON(http.StatusServiceUnavailable, nil)
+		return c.JSON(http.StatusServiceUnavailable, h.healthResponse(Unhealthy))
 	}
 
 	logger.Info().
 		Dur("total_duration", time.Since(start)).
 		Msg("health check passed")
 
-	err := c.JSON(http.StatusOK, nil)
+	err := c.JSON(http.StatusOK, h.healthResponse(Healthy))
 	if err != nil {
 		logger.Error().
 			Str("check_type", "response").
@@ -84,3 +86,18 @@ func (h *HealthHandler) CheckHealth(c echo.Context) error {
 
 	return nil
 }
+
+func (h *HealthHandler) healthResponse(status string) dto.HealthResponse {
+	resp := dto.HealthResponse{Status: status}
+
+	if reporter, ok := h.server.LLM.(llm.BreakerReporter); ok {
+		resp.CircuitBreakers = reporter.CircuitBreakers()
+	}
+
+	if reporter, ok := h.server.LLM.(llm.CoalescingReporter); ok {
+		coalesced := reporter.CoalescedCalls()
+		resp.CoalescedCalls = &coalesced
+	}
+
+	return resp
+}
@@ -8,6 +8,8 @@ import (
 type Handlers struct {
 	Auth    *AuthHandler
 	Chat    *ChatHandler
+	Usage   *UsageHandler
+	Persona *PersonaHandler
 	OpenAPI *OpenAPIHandler
 	Health  *HealthHandler
 }
@@ -16,6 +18,8 @@ func New(s *server.Server, services *service.Services) *Handlers {
 	return &Handlers{
 		Auth:    NewAuthHandler(s, services.Auth),
 		Chat:    NewChatHandler(s, services.Chat),
+		Usage:   NewUsageHandler(s, services.Usage),
+		Persona: NewPersonaHandler(s, services.Persona),
 		Health:  NewHealthHandler(s),
 		OpenAPI: NewOpenAPIHandler(),
 	}
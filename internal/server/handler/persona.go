@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"github.com/shanto-323/axis/internal/model/entity"
+	"github.com/shanto-323/axis/internal/server"
+	"github.com/shanto-323/axis/internal/service"
+)
+
+type PersonaHandler struct {
+	*Handler
+	service service.PersonaService
+}
+
+func NewPersonaHandler(s *server.Server, service service.PersonaService) *PersonaHandler {
+	return &PersonaHandler{
+		Handler: NewHandler(s),
+		service: service,
+	}
+}
+
+func (h *PersonaHandler) CreateHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return Handle(
+			h.Handler,
+			func(c echo.Context, req *dto.PersonaRequest) (*entity.Persona, error) {
+				return h.service.Create(c, req)
+			},
+			http.StatusCreated,
+			&dto.PersonaRequest{},
+		)(c)
+	}
+}
+
+func (h *PersonaHandler) ListHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return Handle(
+			h.Handler,
+			func(c echo.Context, req *dto.PersonaListRequest) ([]entity.Persona, error) {
+				return h.service.List(c, req)
+			},
+			http.StatusOK,
+			&dto.PersonaListRequest{},
+		)(c)
+	}
+}
+
+func (h *PersonaHandler) GetHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return Handle(
+			h.Handler,
+			func(c echo.Context, req *dto.PersonaIDRequest) (*entity.Persona, error) {
+				return h.service.Get(c, req)
+			},
+			http.StatusOK,
+			&dto.PersonaIDRequest{},
+		)(c)
+	}
+}
+
+func (h *PersonaHandler) UpdateHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return Handle(
+			h.Handler,
+			func(c echo.Context, req *dto.UpdatePersonaRequest) (*entity.Persona, error) {
+				return h.service.Update(c, req)
+			},
+			http.StatusOK,
+			&dto.UpdatePersonaRequest{},
+		)(c)
+	}
+}
+
+func (h *PersonaHandler) DeleteHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return HandleNoResponse(
+			h.Handler,
+			func(c echo.Context, req *dto.PersonaIDRequest) error {
+				return h.service.Delete(c, req)
+			},
+			http.StatusNoContent,
+			&dto.PersonaIDRequest{},
+		)(c)
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shanto-323/axis/internal/errs"
+)
+
+const (
+	SSEEventDelta = "delta"
+	SSEEventDone  = "done"
+	SSEEventError = "error"
+)
+
+// SSEStream writes Server-Sent Events to the client. Headers are only sent
+// with the first event, so validation errors still go out as plain JSON.
+type SSEStream struct {
+	c       echo.Context
+	started bool
+}
+
+func NewSSEStream(c echo.Context) *SSEStream {
+	return &SSEStream{c: c}
+}
+
+func (s *SSEStream) Started() bool {
+	return s.started
+}
+
+func (s *SSEStream) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+
+	res := s.c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+
+	// Streams outlive the server write timeout, so lift it for this response.
+	_ = http.NewResponseController(res).SetWriteDeadline(time.Time{})
+
+	res.WriteHeader(http.StatusOK)
+}
+
+func (s *SSEStream) Send(event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	s.start()
+
+	res := s.c.Response()
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	res.Flush()
+
+	return nil
+}
+
+func (s *SSEStream) Error(err error) error {
+	var httpErr *errs.HTTPError
+	if !errors.As(err, &httpErr) {
+		httpErr = errs.NewInternalServerError()
+	}
+
+	return s.Send(SSEEventError, httpErr)
+}
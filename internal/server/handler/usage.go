@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"github.com/shanto-323/axis/internal/server"
+	"github.com/shanto-323/axis/internal/service"
+)
+
+type UsageHandler struct {
+	*Handler
+	service service.UsageService
+}
+
+func NewUsageHandler(s *server.Server, service service.UsageService) *UsageHandler {
+	return &UsageHandler{
+		Handler: NewHandler(s),
+		service: service,
+	}
+}
+
+func (h *UsageHandler) UsageHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return Handle(
+			h.Handler,
+			func(c echo.Context, req *dto.UsageRequest) (*dto.UsageResponse, error) {
+				return h.service.Usage(c, req)
+			},
+			http.StatusOK,
+			&dto.UsageRequest{},
+		)(c)
+	}
+}
+
+func (h *UsageHandler) CostsHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return Handle(
+			h.Handler,
+			func(c echo.Context, req *dto.CostReportQuery) (*dto.CostReportResponse, error) {
+				return h.service.Costs(c, req)
+			},
+			http.StatusOK,
+			&dto.CostReportQuery{},
+		)(c)
+	}
+}
+
+func (h *UsageHandler) CostReportHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return Handle(
+			h.Handler,
+			func(c echo.Context, req *dto.CostReportQuery) (*dto.CostReportResponse, error) {
+				return h.service.CostReport(c, req)
+			},
+			http.StatusOK,
+			&dto.CostReportQuery{},
+		)(c)
+	}
+}
@@ -2,7 +2,9 @@ package middleware
 
 import (
 	"context"
+	"slices"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/shanto-323/axis/internal/errs"
 	"github.com/shanto-323/axis/internal/server"
@@ -40,3 +42,18 @@ func (m *AuthMiddleware) RequireAuth() echo.MiddlewareFunc {
 		}
 	}
 }
+
+// RequireAdmin must run after RequireAuth. It admits only the users listed
+// in server.admin_user_ids.
+func (m *AuthMiddleware) RequireAdmin() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id, ok := c.Get("id").(uuid.UUID)
+			if !ok || !slices.Contains(m.server.Config.Server.AdminUserIDs, id.String()) {
+				return errs.NewForbiddenError("admin access required", false)
+			}
+
+			return next(c)
+		}
+	}
+}
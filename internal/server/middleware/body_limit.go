@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/server"
+)
+
+// chatTextBytes is the room left in a chat request body for everything but
+// its images.
+const chatTextBytes = 1 << 20
+
+type BodyLimit struct {
+	server *server.Server
+}
+
+func NewBodyLimit(s *server.Server) *BodyLimit {
+	return &BodyLimit{
+		server: s,
+	}
+}
+
+// ChatBodyLimit rejects chat requests larger than the most images allowed,
+// base64 encoded, plus the text. The body is cut off at the limit while it
+// is read, so an oversized request is never decoded.
+func (b *BodyLimit) ChatBodyLimit() echo.MiddlewareFunc {
+	maxBytes, maxCount := b.server.Config.AiManage.Images.Limits()
+	limit := int64(maxCount*base64.StdEncoding.EncodedLen(maxBytes) + chatTextBytes)
+
+	code := "REQUEST_TOO_LARGE"
+	tooLarge := func() error {
+		return errs.NewRequestEntityTooLargeError(fmt.Sprintf("request body must not be larger than %d bytes", limit), true, &code)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.ContentLength > limit {
+				return tooLarge()
+			}
+			req.Body = http.MaxBytesReader(c.Response(), req.Body, limit)
+
+			// Binding reports the cut off body as malformed.
+			err := next(c)
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return tooLarge()
+			}
+			return err
+		}
+	}
+}
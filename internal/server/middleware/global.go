@@ -28,6 +28,8 @@ func (g *Global) CROS() echo.MiddlewareFunc {
 	return middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins:     g.server.Config.Server.CORSAllowedOrigins,
 		AllowCredentials: true,
+		// Browser clients need the request id to cancel a generation.
+		ExposeHeaders: []string{RequestIDHeader},
 	})
 }
 
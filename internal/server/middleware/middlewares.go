@@ -5,6 +5,7 @@ import "github.com/shanto-323/axis/internal/server"
 type Middlewares struct {
 	*Global
 	*RateLimit
+	*BodyLimit
 	*ContextEnhancer
 	*AuthMiddleware
 }
@@ -13,6 +14,7 @@ func New(s *server.Server) *Middlewares {
 	return &Middlewares{
 		Global:          NewGlobal(s),
 		RateLimit:       NewRateLimit(s),
+		BodyLimit:       NewBodyLimit(s),
 		ContextEnhancer: NewContextEnhancer(s),
 		AuthMiddleware:  NewAuthMiddleware(s),
 	}
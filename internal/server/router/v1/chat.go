@@ -9,8 +9,12 @@ import (
 func registerChatRoute(r *echo.Group, h *handler.Handlers, m *middleware.Middlewares) {
 	chatRoute := r.Group("/chat")
 	{
-		chatRoute.Use(m.RequireAuth())
+		chatRoute.Use(m.RequireAuth(), m.ChatBodyLimit())
 		chatRoute.POST("", h.Chat.ChatHandler())
+		chatRoute.POST("/stream", h.Chat.ChatStreamHandler())
+		chatRoute.POST("/compare", h.Chat.CompareHandler())
+		chatRoute.POST("/ensemble", h.Chat.EnsembleHandler())
+		chatRoute.POST("/:request_id/cancel", h.Chat.CancelHandler())
 		chatRoute.GET("/models", h.Chat.ModelHandler())
 		chatRoute.POST("/history", h.Chat.ChatHistoryHandler())
 	}
@@ -0,0 +1,15 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shanto-323/axis/internal/server/handler"
+	"github.com/shanto-323/axis/internal/server/middleware"
+)
+
+func registerImageRoutes(r *echo.Group, h *handler.Handlers, m *middleware.Middlewares) {
+	imageRoute := r.Group("/images")
+	{
+		imageRoute.Use(m.RequireAuth())
+		imageRoute.GET("/:id", h.Chat.ImageHandler())
+	}
+}
@@ -0,0 +1,16 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shanto-323/axis/internal/server/handler"
+	"github.com/shanto-323/axis/internal/server/middleware"
+)
+
+func registerMeRoutes(r *echo.Group, h *handler.Handlers, m *middleware.Middlewares) {
+	meRoute := r.Group("/me")
+	{
+		meRoute.Use(m.RequireAuth())
+		meRoute.GET("/usage", h.Usage.UsageHandler())
+		meRoute.GET("/costs", h.Usage.CostsHandler())
+	}
+}
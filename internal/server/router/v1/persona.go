@@ -0,0 +1,19 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shanto-323/axis/internal/server/handler"
+	"github.com/shanto-323/axis/internal/server/middleware"
+)
+
+func registerPersonaRoutes(r *echo.Group, h *handler.Handlers, m *middleware.Middlewares) {
+	personaRoute := r.Group("/personas")
+	{
+		personaRoute.Use(m.RequireAuth())
+		personaRoute.POST("", h.Persona.CreateHandler())
+		personaRoute.GET("", h.Persona.ListHandler())
+		personaRoute.GET("/:id", h.Persona.GetHandler())
+		personaRoute.PUT("/:id", h.Persona.UpdateHandler())
+		personaRoute.DELETE("/:id", h.Persona.DeleteHandler())
+	}
+}
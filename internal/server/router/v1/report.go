@@ -0,0 +1,15 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shanto-323/axis/internal/server/handler"
+	"github.com/shanto-323/axis/internal/server/middleware"
+)
+
+func registerReportRoutes(r *echo.Group, h *handler.Handlers, m *middleware.Middlewares) {
+	reportRoute := r.Group("/reports")
+	{
+		reportRoute.Use(m.RequireAuth(), m.RequireAdmin())
+		reportRoute.GET("/costs", h.Usage.CostReportHandler())
+	}
+}
@@ -10,4 +10,12 @@ func RegisterV1Routes(r *echo.Group, h *handler.Handlers, m *middleware.Middlewa
 	registerAuthRoutes(r, h)
 
 	registerChatRoute(r, h, m)
+
+	registerImageRoutes(r, h, m)
+
+	registerMeRoutes(r, h, m)
+
+	registerPersonaRoutes(r, h, m)
+
+	registerReportRoutes(r, h, m)
 }
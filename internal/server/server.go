@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"time"
 
@@ -10,7 +11,9 @@ import (
 	"github.com/shanto-323/axis/config"
 	"github.com/shanto-323/axis/internal/database"
 	"github.com/shanto-323/axis/internal/llm"
-	"github.com/shanto-323/axis/internal/llm/openrouter"
+	"github.com/shanto-323/axis/internal/llm/cache"
+	"github.com/shanto-323/axis/internal/llm/registry"
+	"github.com/shanto-323/axis/internal/llm/tools"
 	"github.com/shanto-323/axis/pkg/tracer"
 )
 
@@ -35,7 +38,10 @@ func New(cfg *config.Config, logger *zerolog.Logger) (*Server, error) {
 		return nil, err
 	}
 
-	llm := openrouter.NewOpenrouter(cfg, logger, tracer.Tracer)
+	llm, err := registry.New(cfg, logger, tracer.Tracer, tools.New(cfg.AiManage.Tools, db), cache.New(cfg.AiManage.Cache, db))
+	if err != nil {
+		return nil, err
+	}
 
 	return &Server{
 		Config:   cfg,
@@ -70,6 +76,21 @@ func (s *Server) Run() error {
 	return s.httpServer.ListenAndServe()
 }
 
+func (s *Server) Reload() {
+	reloader, ok := s.LLM.(llm.Reloader)
+	if !ok {
+		return
+	}
+
+	if err := reloader.Reload(); err != nil {
+		s.Logger.Error().Err(err).Msg("failed to reload model catalog")
+	}
+}
+
 func (s *Server) Stop(ctx context.Context) error {
+	if closer, ok := s.LLM.(io.Closer); ok {
+		_ = closer.Close()
+	}
+
 	return s.Database.Close()
 }
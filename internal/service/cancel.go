@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"github.com/shanto-323/axis/internal/server/middleware"
+)
+
+// errGenerationCancelled is the cause of generations cancelled through
+// Cancel. A client going away cancels with context.Canceled.
+var errGenerationCancelled = errors.New("generation cancelled by the user")
+
+type generationKey struct {
+	userId    uuid.UUID
+	requestId string
+}
+
+// generation is a pointer so an entry can be removed with
+// CompareAndDelete even when a client reuses a request id.
+type generation struct {
+	cancel context.CancelCauseFunc
+}
+
+// trackGeneration makes the generation of the current request cancellable
+// through Cancel until done is called.
+func (s *chatService) trackGeneration(ctx context.Context, c echo.Context, userId uuid.UUID) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	key := generationKey{userId: userId, requestId: middleware.GetRequestID(c)}
+	g := &generation{cancel: cancel}
+	s.generations.Store(key, g)
+
+	return ctx, func() {
+		s.generations.CompareAndDelete(key, g)
+		cancel(nil)
+	}
+}
+
+// Cancel stops a chat request of the user that is still generating. The
+// request itself answers with the log of the cancelled generation. Only
+// requests served by this instance can be cancelled.
+func (s *chatService) Cancel(c echo.Context, payload *dto.CancelRequest) (*dto.CancelResponse, error) {
+	ctx := c.Request().Context()
+
+	ctx, span := s.tracer.Start(ctx, "service")
+	defer span.End()
+
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	userId, ok := c.Get("id").(uuid.UUID)
+	if !ok {
+		return nil, errs.NewInternalServerError()
+	}
+
+	g, ok := s.generations.Load(generationKey{userId: userId, requestId: payload.RequestID})
+	if !ok {
+		code := "GENERATION_NOT_FOUND"
+		return nil, errs.NewNotFoundError("no generation in progress for request "+payload.RequestID, true, &code)
+	}
+	g.(*generation).cancel(errGenerationCancelled)
+
+	middleware.GetLogger(c).Info().
+		Str("event", "generation-cancel").
+		Str("cancelled_request_id", payload.RequestID).
+		Msg("generation cancelled")
+
+	return &dto.CancelResponse{RequestID: payload.RequestID, Cancelled: true}, nil
+}
@@ -2,33 +2,53 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/shanto-323/axis/config"
 	"github.com/shanto-323/axis/internal/database"
 	"github.com/shanto-323/axis/internal/errs"
 	"github.com/shanto-323/axis/internal/llm"
+	"github.com/shanto-323/axis/internal/llm/tools"
 	"github.com/shanto-323/axis/internal/model"
 	"github.com/shanto-323/axis/internal/model/dto"
 	"github.com/shanto-323/axis/internal/model/entity"
+	"github.com/shanto-323/axis/internal/server/middleware"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type ChatService interface {
 	AvailableModels(c echo.Context) *[]dto.LLMModel
 	Chat(c echo.Context, payload *dto.ChatRequest) (*entity.ConversationLog, error)
+	ChatStream(c echo.Context, payload *dto.ChatRequest, onDelta llm.StreamFunc) (*entity.ConversationLog, error)
+	Compare(c echo.Context, payload *dto.CompareRequest) (*dto.CompareResponse, error)
+	Ensemble(c echo.Context, payload *dto.EnsembleRequest) (*dto.EnsembleResponse, error)
 	ChatHistory(c echo.Context, payload *dto.ConversationHistoryQuery) (*model.PaginatedResponse[entity.ConversationLog], error)
+	Image(c echo.Context, payload *dto.ImageIDRequest) (*entity.Image, error)
+	Cancel(c echo.Context, payload *dto.CancelRequest) (*dto.CancelResponse, error)
 }
 
 type chatService struct {
+	cfg    *config.Config
 	db     database.Database
 	llm    llm.LLM
 	tracer trace.Tracer
+
+	// summarizing holds the conversations being summarized.
+	summarizing sync.Map
+	// generations holds the cancellable generations in progress by
+	// generationKey.
+	generations sync.Map
 }
 
-func NewChatService(llm llm.LLM, db database.Database, tracer trace.Tracer) *chatService {
+func NewChatService(cfg *config.Config, llm llm.LLM, db database.Database, tracer trace.Tracer) *chatService {
 	return &chatService{
+		cfg:    cfg,
 		db:     db,
 		llm:    llm,
 		tracer: tracer,
@@ -43,6 +63,31 @@ func (s *chatService) AvailableModels(c echo.Context) *[]dto.LLMModel {
 }
 
 func (s *chatService) Chat(c echo.Context, payload *dto.ChatRequest) (*entity.ConversationLog, error) {
+	// A plain response carries the request id only once it is done, so only
+	// a client that picked its own id can cancel it.
+	cancellable := c.Request().Header.Get(middleware.RequestIDHeader) != ""
+	return s.chat(c, payload, cancellable, s.llm.GenerateResponse)
+}
+
+func (s *chatService) ChatStream(c echo.Context, payload *dto.ChatRequest, onDelta llm.StreamFunc) (*entity.ConversationLog, error) {
+	// Invalid JSON could not be repaired once it has been streamed.
+	if payload.ResponseFormat.IsJSON() {
+		return nil, errs.NewBadRequestError("response_format is not supported for streaming, use /chat", true, nil, nil, nil)
+	}
+
+	// A stream sends the request id with its first event.
+	return s.chat(c, payload, true, func(ctx context.Context, payload *dto.ChatRequest, history []dto.ChatMessage) (*dto.ConversationLogResponse, error) {
+		return s.llm.GenerateStreamResponse(ctx, payload, history, onDelta)
+	})
+}
+
+// generateFunc produces the answer to a prepared chat request.
+type generateFunc func(ctx context.Context, payload *dto.ChatRequest, history []dto.ChatMessage) (*dto.ConversationLogResponse, error)
+
+// chat runs a chat request: it checks the quota, applies the persona and
+// builds the context from the conversation, has generate answer and stores
+// the answer. A cancellable request can be stopped through Cancel.
+func (s *chatService) chat(c echo.Context, payload *dto.ChatRequest, cancellable bool, generate generateFunc) (*entity.ConversationLog, error) {
 	ctx := c.Request().Context()
 
 	ctx, span := s.tracer.Start(ctx, "service")
@@ -55,22 +100,287 @@ func (s *chatService) Chat(c echo.Context, payload *dto.ChatRequest) (*entity.Co
 		return nil, errs.NewInternalServerError()
 	}
 
-	llmResponse, err := s.llm.GenerateResponse(ctx, payload)
+	if err := checkQuota(ctx, s.db, userId, 1); err != nil {
+		return nil, err
+	}
+
+	persona, err := s.applyPersona(ctx, userId, payload)
 	if err != nil {
 		return nil, err
 	}
 
-	cLog := entity.ConversationLog{
-		BaseLV:       llmResponse.BaseLV,
-		UserID:       userId,
-		TextQuery:    llmResponse.TextQuery,
-		ResponseText: llmResponse.ResponseText,
+	payload.Attachments, err = s.readImages(payload)
+	if err != nil {
+		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	conversation, history, summary, err := s.loadConversation(ctx, userId, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	history = withSystemPrompt(history, payload, persona)
+	history, truncation, err := s.fitContext(payload, history, summary)
+	if err != nil {
+		return nil, err
+	}
+
+	// The answer is stored even when the client goes away or cancels.
+	dbCtx := context.WithoutCancel(ctx)
+
+	if cancellable {
+		var done func()
+		ctx, done = s.trackGeneration(ctx, c, userId)
+		defer done()
+	}
+
+	ctx = tools.WithUserID(ctx, userId)
+	payload.NoCache = noCache(c.Request().Header)
+
+	llmResponse, genErr := generate(ctx, payload, history)
+	if llmResponse == nil {
+		return nil, genErr
+	}
+
+	conversationId, err := s.saveConversation(dbCtx, conversation, llmResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	imageIds, err := s.storeImages(dbCtx, userId, payload.Attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	cLog, err := s.saveConversationLog(dbCtx, &entity.ConversationLog{
+		UserID:         userId,
+		ConversationID: conversationId,
+		PersonaID:      personaID(persona),
+		ImageIDs:       imageIds,
+		Truncation:     truncation,
+	}, llmResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	// A failed generation is stored for its usage, then reported.
+	if genErr != nil {
+		return nil, genErr
+	}
+
+	if conversationId != nil {
+		s.summarizeLater(dbCtx, middleware.GetLogger(c), *conversationId)
+	}
+
+	if payload.HideReasoning {
+		cLog.Reasoning = nil
+	}
+
+	return cLog, nil
+}
+
+// saveConversationLog completes cLog, which carries who asked and in which
+// context, with the generated answer and its estimated cost and persists it.
+func (s *chatService) saveConversationLog(ctx context.Context, cLog *entity.ConversationLog, llmResponse *dto.ConversationLogResponse) (*entity.ConversationLog, error) {
+	cLog.BaseLV = llmResponse.BaseLV
+	cLog.BaseGeneration = llmResponse.BaseGeneration
+	cLog.TextQuery = llmResponse.TextQuery
+	cLog.ResponseText = llmResponse.ResponseText
+	cLog.Reasoning = llmResponse.Reasoning
+	cLog.Status = llmResponse.Status
+	cLog.FallbackFrom = llmResponse.FallbackFrom
+	cLog.Params = llmResponse.Params
+	cLog.ToolCalls = llmResponse.ToolCalls
+	cLog.Cached = llmResponse.Cached
+	cLog.Coalesced = llmResponse.Coalesced
+
+	// Responses cached before logs had a status are complete.
+	if cLog.Status == "" {
+		cLog.Status = entity.ConversationLogStatusCompleted
+	}
+
+	// Cached and coalesced answers cost nothing upstream.
+	if m, ok := s.llm.GetModel(llmResponse.LLMModelName); ok && !llmResponse.Cached && !llmResponse.Coalesced {
+		cLog.Cost = estimateCost(m, llmResponse.BaseGeneration)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	return s.db.CreateConversationLog(ctx, &cLog)
+	return s.db.CreateConversationLog(ctx, cLog)
+}
+
+func personaID(persona *entity.Persona) *uuid.UUID {
+	if persona == nil {
+		return nil
+	}
+	return &persona.ID
+}
+
+// noCache reports whether the client asked to bypass the response cache
+// with Cache-Control: no-cache or no-store.
+func noCache(header http.Header) bool {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "no-cache", "no-store":
+			return true
+		}
+	}
+	return false
+}
+
+// estimateCost prices a generation with the model's per-million token rates.
+func estimateCost(m *dto.LLMModel, usage model.BaseGeneration) float64 {
+	return (float64(usage.PromptTokens)*m.InputPrice + float64(usage.CompletionTokens)*m.OutputPrice) / 1_000_000
+}
+
+// applyPersona loads the requested persona, if any, and fills in the model
+// when the request leaves it empty: the persona's default model first, then
+// the server default. Unset generation parameters come from the persona.
+func (s *chatService) applyPersona(ctx context.Context, userId uuid.UUID, payload *dto.ChatRequest) (*entity.Persona, error) {
+	var persona *entity.Persona
+
+	if payload.PersonaID != nil {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		p, err := s.db.GetPersonaByID(ctx, userId, *payload.PersonaID)
+		if err != nil {
+			return nil, err
+		}
+		persona = p
+	}
+
+	if payload.Model == "" && persona != nil {
+		payload.Model = persona.DefaultModel
+	}
+	if payload.Model == "" {
+		payload.Model = s.cfg.AiManage.ChatModel()
+	}
+
+	if persona != nil {
+		payload.GenerationParams = payload.GenerationParams.WithDefaults(persona.Params)
+	}
+
+	// Unknown models are reported by the LLM layer.
+	if m, ok := s.llm.GetModel(payload.Model); ok {
+		if err := checkParams(m, payload.GenerationParams); err != nil {
+			return nil, err
+		}
+	}
+
+	return persona, nil
+}
+
+// checkParams validates generation parameters against the limits of the
+// requested model.
+func checkParams(m *dto.LLMModel, params model.GenerationParams) error {
+	var fieldErrors []errs.FieldError
+
+	if params.MaxTokens != nil && m.MaxOutputTokens > 0 && *params.MaxTokens > m.MaxOutputTokens {
+		fieldErrors = append(fieldErrors, errs.FieldError{
+			Field: "max_tokens",
+			Error: fmt.Sprintf("must not exceed %d for model %s", m.MaxOutputTokens, m.Name),
+		})
+	}
+	if params.ReasoningEffort != "" && !m.Reasoning {
+		fieldErrors = append(fieldErrors, errs.FieldError{
+			Field: "reasoning_effort",
+			Error: fmt.Sprintf("is not supported by model %s", m.Name),
+		})
+	}
+
+	if len(fieldErrors) > 0 {
+		return errs.NewBadRequestError("Validation failed", false, nil, fieldErrors, nil)
+	}
+	return nil
+}
+
+// withSystemPrompt puts the inline or persona system prompt ahead of the
+// conversation history.
+func withSystemPrompt(history []dto.ChatMessage, payload *dto.ChatRequest, persona *entity.Persona) []dto.ChatMessage {
+	prompt := payload.SystemPrompt
+	if prompt == "" && persona != nil {
+		prompt = persona.SystemPrompt
+	}
+	if prompt == "" {
+		return history
+	}
+
+	return append([]dto.ChatMessage{{Role: dto.RoleSystem, Content: prompt}}, history...)
+}
+
+// loadConversation resolves the conversation a chat request belongs to and
+// returns its prior turns as model messages, with the turns covered by the
+// latest summary replaced by that summary. A request without a
+// conversation_id starts a new conversation, which is not stored until it
+// has an answer.
+func (s *chatService) loadConversation(ctx context.Context, userId uuid.UUID, payload *dto.ChatRequest) (*entity.Conversation, []dto.ChatMessage, *entity.ConversationSummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if payload.ConversationID == nil {
+		return &entity.Conversation{
+			BaseLLMModel: model.BaseLLMModel{LLMModelName: payload.Model},
+			UserID:       userId,
+			Title:        conversationTitle(payload.Message),
+		}, nil, nil, nil
+	}
+
+	conversation, err := s.db.GetConversationByID(ctx, userId, *payload.ConversationID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	logs, err := s.db.GetHistoryForLLM(ctx, conversation.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// A summary by an outdated summarizer is still used until it has been
+	// regenerated.
+	summary, err := s.db.GetLatestConversationSummary(ctx, conversation.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if summary != nil {
+		return conversation, summaryMessages(summary, *logs), summary, nil
+	}
+
+	return conversation, turnMessages(*logs), nil, nil
+}
+
+// saveConversation stores a new conversation once it has an answer to
+// continue from, so failed requests and requests cancelled before any text
+// leave no empty conversation behind. It returns the id the answer is
+// logged under, nil when there is none.
+func (s *chatService) saveConversation(ctx context.Context, conversation *entity.Conversation, llmResponse *dto.ConversationLogResponse) (*uuid.UUID, error) {
+	if conversation.ID != uuid.Nil {
+		return &conversation.ID, nil
+	}
+
+	if llmResponse.Status == entity.ConversationLogStatusFailed || llmResponse.ResponseText == "" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	conversation, err := s.db.CreateConversation(ctx, conversation)
+	if err != nil {
+		return nil, err
+	}
+	return &conversation.ID, nil
+}
+
+func conversationTitle(message string) string {
+	const maxTitleLength = 60
+
+	title := []rune(strings.TrimSpace(message))
+	if len(title) > maxTitleLength {
+		return string(title[:maxTitleLength]) + "..."
+	}
+	return string(title)
 }
 
 func (s *chatService) ChatHistory(c echo.Context, payload *dto.ConversationHistoryQuery) (*model.PaginatedResponse[entity.ConversationLog], error) {
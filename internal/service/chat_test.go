@@ -0,0 +1,126 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/shanto-323/axis/config"
+	"github.com/shanto-323/axis/internal/database/mock"
+	"github.com/shanto-323/axis/internal/llm/registry"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"github.com/shanto-323/axis/internal/model/entity"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// newChatService runs the chat service on the mock database and a fake
+// provider running script.
+func newChatService(t *testing.T, script string) *chatService {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "script.json")
+	if err := os.WriteFile(path, []byte(script), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{}
+	cfg.AiManage.Providers = map[string]config.ProviderConfig{
+		"openrouter": {Type: config.ProviderTypeFake, Script: path},
+	}
+
+	log := zerolog.Nop()
+	tracer := noop.NewTracerProvider().Tracer("")
+
+	llm, err := registry.New(cfg, &log, tracer, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = llm.Close() })
+
+	db, err := mock.New(cfg, &log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewChatService(cfg, llm, db, tracer)
+}
+
+// newContext is the context of a request by a signed in user.
+func newContext() echo.Context {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	c.Set("id", uuid.New())
+	return c
+}
+
+func TestChatStoresTheAnswer(t *testing.T) {
+	s := newChatService(t, `{"rules": [
+		{"match": "hello", "response": "Hi there."}
+	]}`)
+
+	cLog, err := s.Chat(newContext(), &dto.ChatRequest{Model: "llama-70b", Message: "hello"})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+
+	if cLog.ResponseText != "Hi there." || cLog.Status != entity.ConversationLogStatusCompleted {
+		t.Errorf("answer = %q (%s), want the scripted one completed", cLog.ResponseText, cLog.Status)
+	}
+	if cLog.ConversationID == nil {
+		t.Error("ConversationID = nil, want a new conversation")
+	}
+	if cLog.PromptTokens == 0 || cLog.CompletionTokens == 0 {
+		t.Errorf("usage = %d/%d, want both counted", cLog.PromptTokens, cLog.CompletionTokens)
+	}
+}
+
+func TestChatStreamSendsTheStoredAnswer(t *testing.T) {
+	s := newChatService(t, `{"chunk_size": 2, "rules": [
+		{"response": "One two three four five."}
+	]}`)
+
+	var deltas []string
+	cLog, err := s.ChatStream(newContext(), &dto.ChatRequest{Model: "llama-70b", Message: "count"}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+
+	if len(deltas) < 2 {
+		t.Errorf("got %d deltas, want the answer in chunks", len(deltas))
+	}
+	if streamed := strings.Join(deltas, ""); streamed != cLog.ResponseText || streamed != "One two three four five." {
+		t.Errorf("streamed %q, stored %q", streamed, cLog.ResponseText)
+	}
+}
+
+func TestCompareReportsFailedModelsApart(t *testing.T) {
+	s := newChatService(t, `{"rules": [
+		{"model": "meta-llama/llama-3.3-70b-instruct:free", "error": "400"},
+		{"response": "Paris."}
+	]}`)
+
+	response, err := s.Compare(newContext(), &dto.CompareRequest{
+		Message: "capital of France?",
+		Models:  []string{"llama-70b", "nemotron-30b"},
+	})
+	if err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+
+	failed, answered := response.Results[0], response.Results[1]
+	if failed.Error == nil {
+		t.Errorf("llama-70b answered %q, want an error", failed.ResponseText)
+	}
+	if answered.Error != nil || answered.ResponseText != "Paris." || answered.LogID == nil {
+		t.Errorf("nemotron-30b = %+v, want a stored answer", answered)
+	}
+}
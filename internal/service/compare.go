@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/model"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"github.com/shanto-323/axis/internal/model/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const defaultCompareTimeout = 60 * time.Second
+
+// Compare sends one message to every requested model at once. A model that
+// fails or runs out of time is reported in its result without failing the
+// others. Answers are stored as conversation logs sharing a comparison id.
+func (s *chatService) Compare(c echo.Context, payload *dto.CompareRequest) (*dto.CompareResponse, error) {
+	ctx := c.Request().Context()
+
+	ctx, span := s.tracer.Start(ctx, "service")
+	defer span.End()
+
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	userId, ok := c.Get("id").(uuid.UUID)
+	if !ok {
+		return nil, errs.NewInternalServerError()
+	}
+
+	if err := s.checkCompareModels(payload.Models, payload.GenerationParams); err != nil {
+		return nil, err
+	}
+
+	if err := checkQuota(ctx, s.db, userId, len(payload.Models)); err != nil {
+		return nil, err
+	}
+
+	timeout := s.compareTimeout()
+
+	var history []dto.ChatMessage
+	if payload.SystemPrompt != "" {
+		history = []dto.ChatMessage{{Role: dto.RoleSystem, Content: payload.SystemPrompt}}
+	}
+
+	comparisonId := uuid.New()
+	results := s.fanOut(ctx, "event.compare", entity.ConversationLog{
+		UserID:       userId,
+		ComparisonID: &comparisonId,
+	}, payload.Models, payload.Message, payload.GenerationParams, history, timeout)
+
+	return &dto.CompareResponse{
+		ComparisonID: comparisonId,
+		Query:        payload.Message,
+		Results:      results,
+	}, nil
+}
+
+// compareTimeout bounds each model's answer in a comparison or ensemble.
+func (s *chatService) compareTimeout() time.Duration {
+	if s.cfg.AiManage.Compare.Timeout == 0 {
+		return defaultCompareTimeout
+	}
+	return s.cfg.AiManage.Compare.Timeout
+}
+
+// fanOut asks every model the same message at once and stores each answer as
+// a copy of cLog.
+func (s *chatService) fanOut(ctx context.Context, spanName string, cLog entity.ConversationLog, models []string, message string, params model.GenerationParams, history []dto.ChatMessage, timeout time.Duration) []dto.CompareResult {
+	results := make([]dto.CompareResult, len(models))
+
+	var wg sync.WaitGroup
+	for i, m := range models {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = s.answer(ctx, spanName, cLog, &dto.ChatRequest{
+				Model:            m,
+				Message:          message,
+				GenerationParams: params,
+			}, history, timeout)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// answer runs one model call in its own span and stores the answer as cLog.
+// Failures are reported in the result rather than returned.
+func (s *chatService) answer(ctx context.Context, spanName string, cLog entity.ConversationLog, request *dto.ChatRequest, history []dto.ChatMessage, timeout time.Duration) dto.CompareResult {
+	ctx, span := s.tracer.Start(ctx, spanName, trace.WithAttributes(attribute.String("llm.alias", request.Model)))
+	defer span.End()
+
+	result := dto.CompareResult{Model: request.Model}
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Answers that were paid for are stored even when the client goes away.
+	dbCtx := context.WithoutCancel(ctx)
+
+	start := time.Now()
+	llmResponse, err := s.llm.GenerateResponse(callCtx, request, history)
+	result.LatencyMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		span.RecordError(err)
+		msg := compareError(callCtx, err, timeout)
+		result.Error = &msg
+
+		// A failed answer may still have spent tokens.
+		if llmResponse != nil {
+			if saved, err := s.saveConversationLog(dbCtx, &cLog, llmResponse); err == nil {
+				result.LogID = &saved.ID
+				result.Cost = saved.Cost
+			}
+			result.BaseGeneration = llmResponse.BaseGeneration
+		}
+		return result
+	}
+
+	saved, err := s.saveConversationLog(dbCtx, &cLog, llmResponse)
+	if err != nil {
+		span.RecordError(err)
+		msg := "failed to save the answer"
+		result.Error = &msg
+	} else {
+		result.LogID = &saved.ID
+		result.Cost = saved.Cost
+	}
+
+	result.ResponseText = llmResponse.ResponseText
+	result.Reasoning = llmResponse.Reasoning
+	result.FallbackFrom = llmResponse.FallbackFrom
+	result.BaseGeneration = llmResponse.BaseGeneration
+
+	return result
+}
+
+// checkCompareModels rejects unknown models and parameters a model does
+// not support before any of them is called.
+func (s *chatService) checkCompareModels(models []string, params model.GenerationParams) error {
+	var fieldErrors []errs.FieldError
+
+	for i, name := range models {
+		m, ok := s.llm.GetModel(name)
+		if !ok {
+			fieldErrors = append(fieldErrors, errs.FieldError{
+				Field: fmt.Sprintf("models[%d]", i),
+				Error: "no such model: " + name,
+			})
+			continue
+		}
+
+		if err := checkParams(m, params); err != nil {
+			var httpErr *errs.HTTPError
+			if errors.As(err, &httpErr) {
+				fieldErrors = append(fieldErrors, httpErr.Errors...)
+			}
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return errs.NewBadRequestError("Validation failed", false, nil, fieldErrors, nil)
+	}
+	return nil
+}
+
+func compareError(ctx context.Context, err error, timeout time.Duration) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Sprintf("no answer within %s", timeout)
+	}
+
+	var httpErr *errs.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Message
+	}
+	return err.Error()
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/llm/tokens"
+	"github.com/shanto-323/axis/internal/model"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"github.com/shanto-323/axis/internal/model/entity"
+)
+
+const defaultOutputReserve = 4096
+
+// fitContext drops the oldest conversation turns until the system prompt,
+// the summary of earlier turns, the remaining turns and the new message fit
+// the requested model's context window, leaving room for the answer. It
+// reports what was dropped or summarized, or nil when the whole history was
+// sent. Models without a known context length are sent the whole history.
+//
+// Fallback models are assumed to have a context window at least as large.
+func (s *chatService) fitContext(payload *dto.ChatRequest, history []dto.ChatMessage, summary *entity.ConversationSummary) ([]dto.ChatMessage, *model.ContextTruncation, error) {
+	truncation := &model.ContextTruncation{}
+	if summary != nil {
+		truncation.SummarizedTurns = summary.CoveredTurns
+		truncation.SummaryVersion = summary.Version
+	}
+
+	m, ok := s.llm.GetModel(payload.Model)
+	if !ok || m.ContextLength == 0 {
+		if summary == nil {
+			return history, nil, nil
+		}
+		return history, truncation, nil
+	}
+
+	budget := m.ContextLength - s.outputReserve(m, payload.GenerationParams)
+	estimator := tokens.ForModel(m.Model)
+
+	// The system prompt and summary lead the history, the rest are user and
+	// assistant pairs.
+	var system []dto.ChatMessage
+	for len(history) > 0 && history[0].Role == dto.RoleSystem {
+		system = append(system, history[0])
+		history = history[1:]
+	}
+
+	used := estimator.Prompt(system) + estimator.Message(dto.ChatMessage{
+		Role:    dto.RoleUser,
+		Content: payload.Message,
+		Images:  payload.Attachments,
+	})
+	if used > budget {
+		code := "CONTEXT_LENGTH_EXCEEDED"
+		return nil, nil, errs.NewBadRequestError(
+			fmt.Sprintf("the message is too long for model %s: about %d tokens, %d available", m.Name, used, budget),
+			true, &code, nil, nil,
+		)
+	}
+
+	start := len(history)
+	for start >= 2 {
+		turn := estimator.Message(history[start-2]) + estimator.Message(history[start-1])
+		if used+turn > budget {
+			break
+		}
+		used += turn
+		start -= 2
+	}
+
+	if start == 0 && summary == nil {
+		return append(system, history...), nil, nil
+	}
+
+	for _, msg := range history[:start] {
+		truncation.DroppedTokens += estimator.Message(msg)
+	}
+	truncation.DroppedTurns = start / 2
+	truncation.KeptTurns = (len(history) - start) / 2
+	truncation.PromptTokens = used
+	truncation.Budget = budget
+
+	return append(system, history[start:]...), truncation, nil
+}
+
+// outputReserve is the room left for the answer: max_tokens when the
+// request sets it, otherwise the configured reserve, capped at the model's
+// max output tokens and at half the context window.
+func (s *chatService) outputReserve(m *dto.LLMModel, params model.GenerationParams) int {
+	if params.MaxTokens != nil {
+		return *params.MaxTokens
+	}
+
+	reserve := s.cfg.AiManage.Context.OutputReserve
+	if reserve == 0 {
+		reserve = defaultOutputReserve
+	}
+	if m.MaxOutputTokens > 0 {
+		reserve = min(reserve, m.MaxOutputTokens)
+	}
+	return min(reserve, m.ContextLength/2)
+}
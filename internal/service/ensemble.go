@@ -0,0 +1,228 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"github.com/shanto-323/axis/internal/model/entity"
+)
+
+const judgePrompt = `You judge answers that different AI assistants gave to the same question.
+Compare them for correctness, completeness and clarity and decide which candidate is best.`
+
+const synthesizePrompt = judgePrompt + `
+Then write the best possible final answer, keeping what the candidates got right and fixing what they got wrong.
+The final answer is shown to the user as is, so do not mention the candidates in it.`
+
+// verdict is the judge's structured answer. Choice numbers the candidates
+// from 1 in the order they were shown.
+type verdict struct {
+	Choice    int    `json:"choice"`
+	Rationale string `json:"rationale"`
+	Answer    string `json:"answer"`
+}
+
+// Ensemble asks every candidate model the question and has the judge model
+// pick the best answer or synthesize one from all of them. Candidates and
+// the judge run in child spans of the request and are stored as conversation
+// logs sharing an ensemble id.
+//
+// When the judge fails, the first candidate that answered is returned.
+func (s *chatService) Ensemble(c echo.Context, payload *dto.EnsembleRequest) (*dto.EnsembleResponse, error) {
+	ctx := c.Request().Context()
+
+	ctx, span := s.tracer.Start(ctx, "service")
+	defer span.End()
+
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	userId, ok := c.Get("id").(uuid.UUID)
+	if !ok {
+		return nil, errs.NewInternalServerError()
+	}
+
+	judge, err := s.ensembleJudge(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCompareModels(payload.Models, payload.GenerationParams); err != nil {
+		return nil, err
+	}
+
+	// Every candidate and the judge may be called.
+	if err := checkQuota(ctx, s.db, userId, len(payload.Models)+1); err != nil {
+		return nil, err
+	}
+
+	timeout := s.compareTimeout()
+
+	var history []dto.ChatMessage
+	if payload.SystemPrompt != "" {
+		history = []dto.ChatMessage{{Role: dto.RoleSystem, Content: payload.SystemPrompt}}
+	}
+
+	ensembleId := uuid.New()
+	candidateRole := entity.EnsembleRoleCandidate
+	candidates := s.fanOut(ctx, "event.ensemble_candidate", entity.ConversationLog{
+		UserID:       userId,
+		EnsembleID:   &ensembleId,
+		EnsembleRole: &candidateRole,
+	}, payload.Models, payload.Message, payload.GenerationParams, history, timeout)
+
+	var answered []dto.CompareResult
+	for _, candidate := range candidates {
+		if candidate.Error == nil {
+			answered = append(answered, candidate)
+		}
+	}
+	if len(answered) == 0 {
+		return nil, errs.NewServiceUnavailableError("none of the candidate models answered", true, nil)
+	}
+
+	response := &dto.EnsembleResponse{
+		EnsembleID: ensembleId,
+		Query:      payload.Message,
+		Mode:       payload.Mode,
+		Candidates: candidates,
+	}
+
+	if len(answered) == 1 {
+		response.Answer = answered[0].ResponseText
+		response.ChosenModel = &answered[0].Model
+		response.Rationale = fmt.Sprintf("only %s answered, the judge was not asked", answered[0].Model)
+		return response, nil
+	}
+
+	judgeRole := entity.EnsembleRoleJudge
+	result := s.answer(ctx, "event.ensemble_judge", entity.ConversationLog{
+		UserID:       userId,
+		EnsembleID:   &ensembleId,
+		EnsembleRole: &judgeRole,
+	}, &dto.ChatRequest{
+		Model:          judge,
+		Message:        judgeMessage(payload, answered),
+		ResponseFormat: verdictFormat(payload.Mode, len(answered)),
+	}, []dto.ChatMessage{{Role: dto.RoleSystem, Content: systemPromptFor(payload.Mode)}}, timeout)
+	response.Judge = &result
+
+	v, err := parseVerdict(result, len(answered))
+	if err != nil {
+		msg := err.Error()
+		response.Judge.Error = &msg
+		response.Answer = answered[0].ResponseText
+		response.ChosenModel = &answered[0].Model
+		return response, nil
+	}
+
+	chosen := answered[v.Choice-1]
+	response.ChosenModel = &chosen.Model
+	response.Rationale = v.Rationale
+	response.Answer = chosen.ResponseText
+	if payload.Mode == dto.EnsembleModeSynthesize {
+		response.Answer = v.Answer
+	}
+
+	return response, nil
+}
+
+// ensembleJudge resolves the judge model of a request.
+func (s *chatService) ensembleJudge(payload *dto.EnsembleRequest) (string, error) {
+	judge := payload.Judge
+	if judge == "" {
+		judge = s.cfg.AiManage.Ensemble.Judge
+	}
+
+	msg := "is required, no default judge is configured"
+	if judge != "" {
+		if _, ok := s.llm.GetModel(judge); ok {
+			return judge, nil
+		}
+		msg = "no such model: " + judge
+	}
+
+	return "", errs.NewBadRequestError("Validation failed", false, nil, []errs.FieldError{{Field: "judge", Error: msg}}, nil)
+}
+
+func systemPromptFor(mode string) string {
+	if mode == dto.EnsembleModeSynthesize {
+		return synthesizePrompt
+	}
+	return judgePrompt
+}
+
+// judgeMessage lists the question and the numbered candidate answers. Model
+// names are left out so they do not sway the judge.
+func judgeMessage(payload *dto.EnsembleRequest, answered []dto.CompareResult) string {
+	var b strings.Builder
+
+	if payload.SystemPrompt != "" {
+		fmt.Fprintf(&b, "Instructions given to the assistants:\n%s\n\n", payload.SystemPrompt)
+	}
+	fmt.Fprintf(&b, "Question:\n%s\n", payload.Message)
+
+	for i, candidate := range answered {
+		fmt.Fprintf(&b, "\nCandidate %d:\n%s\n", i+1, candidate.ResponseText)
+	}
+
+	return b.String()
+}
+
+// verdictFormat asks for the candidate number and rationale, and in
+// synthesize mode for the final answer.
+func verdictFormat(mode string, candidates int) *dto.ResponseFormat {
+	properties := map[string]any{
+		"choice": map[string]any{
+			"type":        "integer",
+			"description": fmt.Sprintf("The number of the best candidate, 1 to %d.", candidates),
+		},
+		"rationale": map[string]any{
+			"type":        "string",
+			"description": "Why this candidate is best, briefly.",
+		},
+	}
+	required := []any{"choice", "rationale"}
+
+	if mode == dto.EnsembleModeSynthesize {
+		properties["answer"] = map[string]any{
+			"type":        "string",
+			"description": "The final answer to the question.",
+		}
+		required = append(required, "answer")
+	}
+
+	return &dto.ResponseFormat{
+		Type: dto.ResponseFormatJSONSchema,
+		JSONSchema: &dto.JSONSchemaFormat{
+			Name: "verdict",
+			Schema: map[string]any{
+				"type":                 "object",
+				"properties":           properties,
+				"required":             required,
+				"additionalProperties": false,
+			},
+			Strict: true,
+		},
+	}
+}
+
+func parseVerdict(result dto.CompareResult, candidates int) (*verdict, error) {
+	if result.Error != nil {
+		return nil, fmt.Errorf("%s", *result.Error)
+	}
+
+	v := &verdict{}
+	if err := json.Unmarshal([]byte(result.ResponseText), v); err != nil {
+		return nil, fmt.Errorf("the judge's verdict is not valid JSON")
+	}
+	if v.Choice < 1 || v.Choice > candidates {
+		return nil, fmt.Errorf("the judge chose candidate %d of %d", v.Choice, candidates)
+	}
+
+	return v, nil
+}
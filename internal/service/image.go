@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/llm"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"github.com/shanto-323/axis/internal/model/entity"
+)
+
+// imageTypes are the formats vision models generally accept. The type is
+// sniffed from the image data.
+var imageTypes = []string{"image/png", "image/jpeg", "image/webp", "image/gif"}
+
+// readImages decodes the images of a chat request, from base64 or multipart
+// uploads, and checks their number, size and type and that the requested
+// model accepts images.
+func (s *chatService) readImages(payload *dto.ChatRequest) ([]dto.ChatImage, error) {
+	count := len(payload.Images) + len(payload.Files)
+	if count == 0 {
+		return nil, nil
+	}
+
+	maxBytes, maxImages := s.cfg.AiManage.Images.Limits()
+	if count > maxImages {
+		return nil, errs.NewBadRequestError("Validation failed", false, nil, []errs.FieldError{{
+			Field: "images",
+			Error: fmt.Sprintf("must not contain more than %d images", maxImages),
+		}}, nil)
+	}
+
+	if m, ok := s.llm.GetModel(payload.Model); ok && !slices.Contains(m.Modalities, llm.ModalityImage) {
+		code := "MODEL_NOT_VISION_CAPABLE"
+		return nil, errs.NewBadRequestError(fmt.Sprintf("model %s does not accept images", m.Name), true, &code, nil, nil)
+	}
+
+	images := make([]dto.ChatImage, 0, count)
+	var fieldErrors []errs.FieldError
+
+	for i, input := range payload.Images {
+		img, err := decodeImage(input, maxBytes)
+		if err != nil {
+			fieldErrors = append(fieldErrors, errs.FieldError{Field: fmt.Sprintf("images[%d]", i), Error: err.Error()})
+			continue
+		}
+		images = append(images, img)
+	}
+
+	for i, file := range payload.Files {
+		img, err := readImageFile(file, maxBytes)
+		if err != nil {
+			fieldErrors = append(fieldErrors, errs.FieldError{Field: fmt.Sprintf("images[%d]", len(payload.Images)+i), Error: err.Error()})
+			continue
+		}
+		images = append(images, img)
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, errs.NewBadRequestError("Validation failed", false, nil, fieldErrors, nil)
+	}
+
+	return images, nil
+}
+
+// decodeImage decodes plain base64 or a base64 data URL.
+func decodeImage(input dto.ImageInput, maxBytes int) (dto.ChatImage, error) {
+	data := input.Data
+	if rest, ok := strings.CutPrefix(data, "data:"); ok {
+		header, encoded, found := strings.Cut(rest, ",")
+		if !found || !strings.HasSuffix(header, ";base64") {
+			return dto.ChatImage{}, fmt.Errorf("must be a base64 data URL")
+		}
+		data = encoded
+	}
+
+	if base64.StdEncoding.DecodedLen(len(data)) > maxBytes+2 {
+		return dto.ChatImage{}, fmt.Errorf("must not be larger than %d bytes", maxBytes)
+	}
+
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return dto.ChatImage{}, fmt.Errorf("is not valid base64")
+	}
+
+	return checkImage(b, input.ContentType, maxBytes)
+}
+
+func readImageFile(file *multipart.FileHeader, maxBytes int) (dto.ChatImage, error) {
+	if file.Size > int64(maxBytes) {
+		return dto.ChatImage{}, fmt.Errorf("must not be larger than %d bytes", maxBytes)
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return dto.ChatImage{}, fmt.Errorf("could not be read")
+	}
+	defer f.Close()
+
+	b, err := io.ReadAll(io.LimitReader(f, int64(maxBytes)+1))
+	if err != nil {
+		return dto.ChatImage{}, fmt.Errorf("could not be read")
+	}
+
+	return checkImage(b, "", maxBytes)
+}
+
+// checkImage enforces the size limit and sniffs the image type. A declared
+// content type must match the sniffed one.
+func checkImage(b []byte, declared string, maxBytes int) (dto.ChatImage, error) {
+	if len(b) == 0 {
+		return dto.ChatImage{}, fmt.Errorf("is empty")
+	}
+	if len(b) > maxBytes {
+		return dto.ChatImage{}, fmt.Errorf("must not be larger than %d bytes", maxBytes)
+	}
+
+	contentType := http.DetectContentType(b)
+	if !slices.Contains(imageTypes, contentType) {
+		return dto.ChatImage{}, fmt.Errorf("must be one of %s", strings.Join(imageTypes, ", "))
+	}
+	if declared != "" && declared != contentType {
+		return dto.ChatImage{}, fmt.Errorf("content_type %s does not match the image data (%s)", declared, contentType)
+	}
+
+	return dto.ChatImage{ContentType: contentType, Data: b}, nil
+}
+
+// storeImages saves the images of an answered request and returns their ids
+// for the conversation log.
+func (s *chatService) storeImages(ctx context.Context, userId uuid.UUID, images []dto.ChatImage) ([]uuid.UUID, error) {
+	if len(images) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	ids := make([]uuid.UUID, 0, len(images))
+	for _, img := range images {
+		sum := sha256.Sum256(img.Data)
+
+		stored, err := s.db.CreateImage(ctx, &entity.Image{
+			UserID:      userId,
+			ContentType: img.ContentType,
+			Size:        len(img.Data),
+			SHA256:      hex.EncodeToString(sum[:]),
+			Data:        img.Data,
+		})
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, stored.ID)
+	}
+
+	return ids, nil
+}
+
+func (s *chatService) Image(c echo.Context, payload *dto.ImageIDRequest) (*entity.Image, error) {
+	ctx := c.Request().Context()
+
+	ctx, span := s.tracer.Start(ctx, "service")
+	defer span.End()
+
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	userId, ok := c.Get("id").(uuid.UUID)
+	if !ok {
+		return nil, errs.NewInternalServerError()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return s.db.GetImageByID(ctx, userId, payload.ID)
+}
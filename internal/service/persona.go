@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/shanto-323/axis/internal/database"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/llm"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"github.com/shanto-323/axis/internal/model/entity"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type PersonaService interface {
+	Create(c echo.Context, payload *dto.PersonaRequest) (*entity.Persona, error)
+	List(c echo.Context, payload *dto.PersonaListRequest) ([]entity.Persona, error)
+	Get(c echo.Context, payload *dto.PersonaIDRequest) (*entity.Persona, error)
+	Update(c echo.Context, payload *dto.UpdatePersonaRequest) (*entity.Persona, error)
+	Delete(c echo.Context, payload *dto.PersonaIDRequest) error
+}
+
+type personaService struct {
+	db     database.Database
+	llm    llm.LLM
+	tracer trace.Tracer
+}
+
+func NewPersonaService(llm llm.LLM, db database.Database, tracer trace.Tracer) PersonaService {
+	return &personaService{
+		db:     db,
+		llm:    llm,
+		tracer: tracer,
+	}
+}
+
+func (s *personaService) Create(c echo.Context, payload *dto.PersonaRequest) (*entity.Persona, error) {
+	ctx := c.Request().Context()
+
+	ctx, span := s.tracer.Start(ctx, "service")
+	defer span.End()
+
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	userId, ok := c.Get("id").(uuid.UUID)
+	if !ok {
+		return nil, errs.NewInternalServerError()
+	}
+
+	if err := checkPersonaModel(s.llm, payload.DefaultModel); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return s.db.CreatePersona(ctx, newPersona(userId, payload))
+}
+
+func (s *personaService) List(c echo.Context, payload *dto.PersonaListRequest) ([]entity.Persona, error) {
+	ctx := c.Request().Context()
+
+	ctx, span := s.tracer.Start(ctx, "service")
+	defer span.End()
+
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	userId, ok := c.Get("id").(uuid.UUID)
+	if !ok {
+		return nil, errs.NewInternalServerError()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return s.db.ListPersonas(ctx, userId)
+}
+
+func (s *personaService) Get(c echo.Context, payload *dto.PersonaIDRequest) (*entity.Persona, error) {
+	ctx := c.Request().Context()
+
+	ctx, span := s.tracer.Start(ctx, "service")
+	defer span.End()
+
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	userId, ok := c.Get("id").(uuid.UUID)
+	if !ok {
+		return nil, errs.NewInternalServerError()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return s.db.GetPersonaByID(ctx, userId, payload.ID)
+}
+
+func (s *personaService) Update(c echo.Context, payload *dto.UpdatePersonaRequest) (*entity.Persona, error) {
+	ctx := c.Request().Context()
+
+	ctx, span := s.tracer.Start(ctx, "service")
+	defer span.End()
+
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	userId, ok := c.Get("id").(uuid.UUID)
+	if !ok {
+		return nil, errs.NewInternalServerError()
+	}
+
+	if err := checkPersonaModel(s.llm, payload.DefaultModel); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	p := newPersona(userId, &payload.PersonaRequest)
+	p.ID = payload.ID
+
+	return s.db.UpdatePersona(ctx, p)
+}
+
+func (s *personaService) Delete(c echo.Context, payload *dto.PersonaIDRequest) error {
+	ctx := c.Request().Context()
+
+	ctx, span := s.tracer.Start(ctx, "service")
+	defer span.End()
+
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	userId, ok := c.Get("id").(uuid.UUID)
+	if !ok {
+		return errs.NewInternalServerError()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return s.db.DeletePersona(ctx, userId, payload.ID)
+}
+
+func checkPersonaModel(l llm.LLM, name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, ok := l.GetModel(name); !ok {
+		code := "INVALID_MODEL_NAME"
+		return errs.NewBadRequestError("no such model found :"+name, true, &code, nil, nil)
+	}
+	return nil
+}
+
+func newPersona(userId uuid.UUID, payload *dto.PersonaRequest) *entity.Persona {
+	return &entity.Persona{
+		UserID:       userId,
+		Name:         payload.Name,
+		Description:  payload.Description,
+		SystemPrompt: payload.SystemPrompt,
+		DefaultModel: payload.DefaultModel,
+		Params:       payload.Params,
+		Shared:       payload.Shared,
+	}
+}
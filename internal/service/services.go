@@ -5,13 +5,17 @@ import (
 )
 
 type Services struct {
-	Auth AuthService
-	Chat ChatService
+	Auth    AuthService
+	Chat    ChatService
+	Usage   UsageService
+	Persona PersonaService
 }
 
 func New(s *server.Server) *Services {
 	return &Services{
-		Auth: NewAuthService(s.Config, s.Database, s.Tracer.Tracer),
-		Chat: NewChatService(s.LLM, s.Database, s.Tracer.Tracer),
+		Auth:    NewAuthService(s.Config, s.Database, s.Tracer.Tracer),
+		Chat:    NewChatService(s.Config, s.LLM, s.Database, s.Tracer.Tracer),
+		Usage:   NewUsageService(s.Database, s.Tracer.Tracer),
+		Persona: NewPersonaService(s.LLM, s.Database, s.Tracer.Tracer),
 	}
 }
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/shanto-323/axis/internal/llm/tokens"
+	"github.com/shanto-323/axis/internal/model"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"github.com/shanto-323/axis/internal/model/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultSummaryAfterTurns  = 10
+	defaultSummaryAfterTokens = 8000
+	defaultSummaryKeepTurns   = 4
+	defaultSummaryTimeout     = 60 * time.Second
+
+	summaryMaxTokens = 1024
+
+	// summaryPromptVersion must be bumped whenever summaryPrompt changes, so
+	// summaries written with the old prompt are regenerated.
+	summaryPromptVersion = 1
+)
+
+const summaryPrompt = `You maintain a running summary of a conversation between a user and an AI assistant.
+You are given the summary so far, if any, and the turns that follow it. Write an updated summary that replaces both.
+Keep facts, names, numbers, decisions, code identifiers, the user's preferences and questions still open. Drop small talk.
+Write plain prose in the third person, at most 300 words, and answer with the summary only.`
+
+// summarizeLater writes a new summary of the conversation in the background
+// when enough turns have piled up since the last one. At most one summary
+// per conversation is written at a time.
+func (s *chatService) summarizeLater(ctx context.Context, logger *zerolog.Logger, conversationId uuid.UUID) {
+	if s.cfg.AiManage.Summary.Model == "" {
+		return
+	}
+	if _, running := s.summarizing.LoadOrStore(conversationId, struct{}{}); running {
+		return
+	}
+
+	timeout := s.cfg.AiManage.Summary.Timeout
+	if timeout == 0 {
+		timeout = defaultSummaryTimeout
+	}
+
+	// The summary outlives the request but stays in its trace.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+
+	go func() {
+		defer cancel()
+		defer s.summarizing.Delete(conversationId)
+
+		ctx, span := s.tracer.Start(ctx, "event.summarize", trace.WithAttributes(
+			attribute.String("conversation.id", conversationId.String()),
+		))
+		defer span.End()
+
+		summary, err := s.summarize(ctx, conversationId)
+		if err != nil {
+			span.RecordError(err)
+			logger.Warn().
+				Err(err).
+				Str("event", "conversation-summary").
+				Str("conversation_id", conversationId.String()).
+				Msg("failed to summarize conversation")
+			return
+		}
+
+		if summary != nil {
+			span.SetAttributes(attribute.Int("summary.version", summary.Version))
+			logger.Info().
+				Str("event", "conversation-summary").
+				Str("conversation_id", conversationId.String()).
+				Int("version", summary.Version).
+				Int("covered_turns", summary.CoveredTurns).
+				Msg("conversation summarized")
+		}
+	}()
+}
+
+// summarize extends the latest summary with the turns that followed it, or
+// rewrites it from the start when another model or prompt wrote it. It
+// returns nil when no summary is due. Turns are fed to the model in chunks
+// that fit its context window.
+func (s *chatService) summarize(ctx context.Context, conversationId uuid.UUID) (*entity.ConversationSummary, error) {
+	cfg := s.cfg.AiManage.Summary
+
+	m, ok := s.llm.GetModel(cfg.Model)
+	if !ok {
+		return nil, fmt.Errorf("summary model %s is not in the catalog", cfg.Model)
+	}
+
+	latest, err := s.db.GetLatestConversationSummary(ctx, conversationId)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := s.db.GetHistoryForLLM(ctx, conversationId)
+	if err != nil {
+		return nil, err
+	}
+
+	afterTurns, afterTokens, keepTurns := cfg.AfterTurns, cfg.AfterTokens, cfg.KeepTurns
+	if afterTurns == 0 {
+		afterTurns = defaultSummaryAfterTurns
+	}
+	if afterTokens == 0 {
+		afterTokens = defaultSummaryAfterTokens
+	}
+	if keepTurns == 0 {
+		keepTurns = defaultSummaryKeepTurns
+	}
+
+	estimator := tokens.ForModel(m.Model)
+
+	until := len(*logs) - keepTurns
+	stale := latest != nil && (latest.Model != cfg.Model || latest.PromptVersion != summaryPromptVersion)
+
+	var previous string
+	covered := 0
+	if latest != nil && !stale {
+		previous = latest.Summary
+		covered = min(latest.CoveredTurns, len(*logs))
+	}
+	if until <= covered {
+		return nil, nil
+	}
+
+	pending := (*logs)[covered:until]
+	if !stale && len(pending) < afterTurns && estimator.Prompt(turnMessages(pending)) < afterTokens {
+		return nil, nil
+	}
+
+	summary := &entity.ConversationSummary{
+		ConversationID: conversationId,
+		Model:          cfg.Model,
+		PromptVersion:  summaryPromptVersion,
+	}
+
+	maxTokens := summaryMaxTokens
+	for len(pending) > 0 {
+		n := summaryChunk(estimator, m, previous, pending)
+
+		llmResponse, err := s.llm.GenerateResponse(ctx, &dto.ChatRequest{
+			Model:            cfg.Model,
+			Message:          summaryMessage(previous, pending[:n]),
+			GenerationParams: model.GenerationParams{MaxTokens: &maxTokens},
+			NoCache:          true,
+		}, []dto.ChatMessage{{Role: dto.RoleSystem, Content: summaryPrompt}})
+		if err != nil {
+			return nil, err
+		}
+
+		previous = strings.TrimSpace(llmResponse.ResponseText)
+		covered += n
+		pending = pending[n:]
+
+		summary.PromptTokens += llmResponse.PromptTokens
+		summary.CompletionTokens += llmResponse.CompletionTokens
+		if used, ok := s.llm.GetModel(llmResponse.LLMModelName); ok {
+			summary.Cost += estimateCost(used, llmResponse.BaseGeneration)
+		}
+	}
+
+	if previous == "" {
+		return nil, fmt.Errorf("summary model %s returned an empty summary", cfg.Model)
+	}
+
+	summary.Summary = previous
+	summary.CoveredTurns = covered
+
+	return s.db.CreateConversationSummary(ctx, summary)
+}
+
+// summaryChunk is the number of pending turns, at least one, that fit the
+// summary model's context window along with the previous summary.
+func summaryChunk(estimator tokens.Estimator, m *dto.LLMModel, previous string, pending []entity.ConversationLog) int {
+	if m.ContextLength == 0 {
+		return len(pending)
+	}
+
+	budget := m.ContextLength - summaryMaxTokens -
+		estimator.Prompt([]dto.ChatMessage{{Role: dto.RoleSystem, Content: summaryPrompt}}) -
+		estimator.Count(summaryMessage(previous, nil))
+
+	n := 0
+	for n < len(pending) {
+		turn := estimator.Prompt(turnMessages(pending[n : n+1]))
+		if n > 0 && turn > budget {
+			break
+		}
+		budget -= turn
+		n++
+	}
+	return n
+}
+
+func summaryMessage(previous string, turns []entity.ConversationLog) string {
+	var b strings.Builder
+
+	if previous != "" {
+		fmt.Fprintf(&b, "Summary so far:\n%s\n\n", previous)
+	}
+	b.WriteString("Turns that follow:\n")
+	for _, l := range turns {
+		fmt.Fprintf(&b, "\nUser: %s\nAssistant: %s\n", l.TextQuery, l.ResponseText)
+	}
+
+	return b.String()
+}
+
+// turnMessages turns conversation logs into the user and assistant messages
+// sent to a model.
+func turnMessages(logs []entity.ConversationLog) []dto.ChatMessage {
+	messages := make([]dto.ChatMessage, 0, len(logs)*2)
+	for _, l := range logs {
+		messages = append(messages,
+			dto.ChatMessage{Role: dto.RoleUser, Content: l.TextQuery},
+			dto.ChatMessage{Role: dto.RoleAssistant, Content: l.ResponseText},
+		)
+	}
+	return messages
+}
+
+// summaryMessages replaces the turns a summary covers with the summary, as
+// a system message ahead of the remaining turns.
+func summaryMessages(summary *entity.ConversationSummary, logs []entity.ConversationLog) []dto.ChatMessage {
+	covered := min(summary.CoveredTurns, len(logs))
+
+	return append([]dto.ChatMessage{{
+		Role:    dto.RoleSystem,
+		Content: "Summary of the earlier conversation:\n" + summary.Summary,
+	}}, turnMessages(logs[covered:])...)
+}
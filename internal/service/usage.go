@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/shanto-323/axis/internal/database"
+	"github.com/shanto-323/axis/internal/errs"
+	"github.com/shanto-323/axis/internal/model/dto"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type UsageService interface {
+	Usage(c echo.Context, payload *dto.UsageRequest) (*dto.UsageResponse, error)
+	Costs(c echo.Context, payload *dto.CostReportQuery) (*dto.CostReportResponse, error)
+	CostReport(c echo.Context, payload *dto.CostReportQuery) (*dto.CostReportResponse, error)
+}
+
+type usageService struct {
+	db     database.Database
+	tracer trace.Tracer
+}
+
+func NewUsageService(db database.Database, tracer trace.Tracer) UsageService {
+	return &usageService{
+		db:     db,
+		tracer: tracer,
+	}
+}
+
+func (s *usageService) Usage(c echo.Context, payload *dto.UsageRequest) (*dto.UsageResponse, error) {
+	ctx := c.Request().Context()
+
+	ctx, span := s.tracer.Start(ctx, "service")
+	defer span.End()
+
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	userId, ok := c.Get("id").(uuid.UUID)
+	if !ok {
+		return nil, errs.NewInternalServerError()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return usage(ctx, s.db, userId)
+}
+
+// Costs reports the calling user's estimated spend per model.
+func (s *usageService) Costs(c echo.Context, payload *dto.CostReportQuery) (*dto.CostReportResponse, error) {
+	userId, ok := c.Get("id").(uuid.UUID)
+	if !ok {
+		return nil, errs.NewInternalServerError()
+	}
+
+	payload.UserID = &userId
+	return s.CostReport(c, payload)
+}
+
+// CostReport reports estimated spend per user and model, optionally filtered
+// by user and model.
+func (s *usageService) CostReport(c echo.Context, payload *dto.CostReportQuery) (*dto.CostReportResponse, error) {
+	ctx := c.Request().Context()
+
+	ctx, span := s.tracer.Start(ctx, "service")
+	defer span.End()
+
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := s.db.GetCostReport(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.CostReportResponse{
+		From: payload.FromTime,
+		To:   payload.ToTime,
+		Rows: rows,
+	}
+	for _, r := range rows {
+		report.TotalCost += r.Cost
+	}
+
+	return report, nil
+}
+
+// usage reports a user's consumption against their plan for the current
+// UTC day and month.
+func usage(ctx context.Context, db database.Database, userId uuid.UUID) (*dto.UsageResponse, error) {
+	plan, err := db.GetQuotaPlanByUserID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	totals, err := db.GetUsageTotals(ctx, userId, dayStart, monthStart)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.UsageResponse{
+		Plan: plan.Name,
+		Day: dto.UsagePeriod{
+			ResetsAt: dayStart.AddDate(0, 0, 1),
+			Requests: allowance(totals.RequestsToday, plan.RequestsPerDay),
+			Tokens:   allowance(totals.TokensToday, plan.TokensPerDay),
+
+			ReasoningTokens: totals.ReasoningTokensToday,
+		},
+		Month: dto.UsagePeriod{
+			ResetsAt: monthStart.AddDate(0, 1, 0),
+			Requests: allowance(totals.RequestsThisMonth, plan.RequestsPerMonth),
+			Tokens:   allowance(totals.TokensThisMonth, plan.TokensPerMonth),
+
+			ReasoningTokens: totals.ReasoningTokensThisMonth,
+		},
+	}, nil
+}
+
+func allowance(used int, limit *int) dto.UsageAllowance {
+	a := dto.UsageAllowance{
+		Used:  used,
+		Limit: limit,
+	}
+	if limit != nil {
+		remaining := max(*limit-used, 0)
+		a.Remaining = &remaining
+	}
+	return a
+}
+
+// checkQuota rejects the request when any of the user's limits is used up,
+// or when fewer requests are left than the calls it plans to make. Token
+// limits are checked against tokens already spent, so the request that
+// crosses a limit is still served.
+func checkQuota(ctx context.Context, db database.Database, userId uuid.UUID, calls int) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	u, err := usage(ctx, db, userId)
+	if err != nil {
+		return err
+	}
+
+	limits := []struct {
+		name      string
+		allowance dto.UsageAllowance
+		needed    int
+		resetsAt  time.Time
+	}{
+		{"daily request", u.Day.Requests, calls, u.Day.ResetsAt},
+		{"daily token", u.Day.Tokens, 1, u.Day.ResetsAt},
+		{"monthly request", u.Month.Requests, calls, u.Month.ResetsAt},
+		{"monthly token", u.Month.Tokens, 1, u.Month.ResetsAt},
+	}
+
+	code := "QUOTA_EXCEEDED"
+	for _, l := range limits {
+		if l.allowance.Remaining == nil || *l.allowance.Remaining >= l.needed {
+			continue
+		}
+		if *l.allowance.Remaining == 0 {
+			return errs.NewTooManyRequestsError(
+				fmt.Sprintf("%s quota of plan %s exceeded, resets at %s", l.name, u.Plan, l.resetsAt.Format(time.RFC3339)),
+				true,
+				&code,
+			)
+		}
+		return errs.NewTooManyRequestsError(
+			fmt.Sprintf("request needs %d model calls but the %s quota of plan %s has %d left, resets at %s", l.needed, l.name, u.Plan, *l.allowance.Remaining, l.resetsAt.Format(time.RFC3339)),
+			true,
+			&code,
+		)
+	}
+
+	return nil
+}
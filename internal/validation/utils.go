@@ -25,6 +25,11 @@ func (c CustomValidationErrors) Error() string {
 	return "Validation failed"
 }
 
+// NewFieldError reports a single invalid field from a Validate method.
+func NewFieldError(field, message string) CustomValidationErrors {
+	return CustomValidationErrors{{Field: field, message: message}}
+}
+
 func BindAndValidate(ctx echo.Context, payload Validatable) error {
 	if err := ctx.Bind(payload); err != nil {
 		return err
@@ -49,7 +54,10 @@ func extrectValidationErrors(err error) (string, []errs.FieldError) {
 
 	validationErrors, ok := err.(validator.ValidationErrors)
 	if !ok {
-		customValidationError := err.(CustomValidationErrors)
+		customValidationError, ok := err.(CustomValidationErrors)
+		if !ok {
+			return err.Error(), nil
+		}
 		for _, err := range customValidationError {
 			fieldErrors = append(fieldErrors, errs.FieldError{
 				Field: err.Field,